@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	envToken             = "DIGITALOCEAN_TOKEN"
+	envSpacesAccessKeyID = "DIGITALOCEAN_SPACES_ACCESS_KEY_ID"
+	envSpacesAccessKey   = "DIGITALOCEAN_SPACES_ACCESS_KEY_SECRET"
+
+	// defaultAccount is the name of the account configured by DIGITALOCEAN_TOKEN.
+	defaultAccount = "default"
+)
+
+// Account is a DigitalOcean account or team whose resources are collected.
+type Account struct {
+	Name                  string
+	AccessToken           string
+	SpacesAccessKeyID     string
+	SpacesAccessKeySecret string
+}
+
+// Token returns a token or an error.
+func (a Account) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: a.AccessToken}, nil
+}
+
+// HasSpacesKeys returns true if both Spaces Access Key ID and Secret are set.
+func (a Account) HasSpacesKeys() bool {
+	return a.SpacesAccessKeyID != "" && a.SpacesAccessKeySecret != ""
+}
+
+// accountsFromEnv returns the default account from the config
+// and all named accounts from environment variables like
+// DIGITALOCEAN_TOKEN_<NAME> and DIGITALOCEAN_SPACES_ACCESS_KEY_ID_<NAME>.
+// The names are lowercased and the accounts sorted by them.
+func accountsFromEnv(c Config, environ []string) ([]Account, error) {
+	accounts := map[string]*Account{}
+	if c.DigitalOceanToken != "" {
+		accounts[defaultAccount] = &Account{
+			Name:                  defaultAccount,
+			AccessToken:           c.DigitalOceanToken,
+			SpacesAccessKeyID:     c.SpacesAccessKeyID,
+			SpacesAccessKeySecret: c.SpacesAccessKeySecret,
+		}
+	}
+
+	spacesKeys := map[string][2]string{}
+	for _, kv := range environ {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+		key, value := parts[0], parts[1]
+
+		switch {
+		case strings.HasPrefix(key, envToken+"_"):
+			name := strings.ToLower(strings.TrimPrefix(key, envToken+"_"))
+			if name == "" {
+				return nil, fmt.Errorf("account name of %s is empty", key)
+			}
+			if _, ok := accounts[name]; ok {
+				return nil, fmt.Errorf("account %q is configured more than once", name)
+			}
+			accounts[name] = &Account{Name: name, AccessToken: value}
+		case strings.HasPrefix(key, envSpacesAccessKeyID+"_"):
+			name := strings.ToLower(strings.TrimPrefix(key, envSpacesAccessKeyID+"_"))
+			keys := spacesKeys[name]
+			keys[0] = value
+			spacesKeys[name] = keys
+		case strings.HasPrefix(key, envSpacesAccessKey+"_"):
+			name := strings.ToLower(strings.TrimPrefix(key, envSpacesAccessKey+"_"))
+			keys := spacesKeys[name]
+			keys[1] = value
+			spacesKeys[name] = keys
+		}
+	}
+
+	for name, keys := range spacesKeys {
+		account, ok := accounts[name]
+		if !ok {
+			return nil, fmt.Errorf("spaces keys are set for account %q without a token", name)
+		}
+		account.SpacesAccessKeyID = keys[0]
+		account.SpacesAccessKeySecret = keys[1]
+	}
+
+	result := make([]Account, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, *account)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccountsFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   Config
+		environ  []string
+		accounts []Account
+		err      bool
+	}{
+		{
+			name:     "none",
+			accounts: []Account{},
+		},
+		{
+			name: "single token",
+			config: Config{
+				DigitalOceanToken:     "token",
+				SpacesAccessKeyID:     "id",
+				SpacesAccessKeySecret: "secret",
+			},
+			// The default account's variables are parsed into the config, not from the environment.
+			environ: []string{"DIGITALOCEAN_TOKEN=token", "DIGITALOCEAN_SPACES_ACCESS_KEY_ID=id", "DIGITALOCEAN_SPACES_ACCESS_KEY_SECRET=secret"},
+			accounts: []Account{
+				{Name: "default", AccessToken: "token", SpacesAccessKeyID: "id", SpacesAccessKeySecret: "secret"},
+			},
+		},
+		{
+			name:   "several names",
+			config: Config{DigitalOceanToken: "token"},
+			environ: []string{
+				"DIGITALOCEAN_TOKEN_PAYMENTS=payments-token",
+				"DIGITALOCEAN_TOKEN_Analytics=analytics-token",
+				"HOME=/root",
+			},
+			accounts: []Account{
+				{Name: "analytics", AccessToken: "analytics-token"},
+				{Name: "default", AccessToken: "token"},
+				{Name: "payments", AccessToken: "payments-token"},
+			},
+		},
+		{
+			name: "spaces keys",
+			environ: []string{
+				"DIGITALOCEAN_TOKEN_PAYMENTS=payments-token",
+				"DIGITALOCEAN_SPACES_ACCESS_KEY_ID_PAYMENTS=id",
+				"DIGITALOCEAN_SPACES_ACCESS_KEY_SECRET_PAYMENTS=secret",
+				"DIGITALOCEAN_TOKEN_ANALYTICS=analytics-token",
+			},
+			accounts: []Account{
+				{Name: "analytics", AccessToken: "analytics-token"},
+				{Name: "payments", AccessToken: "payments-token", SpacesAccessKeyID: "id", SpacesAccessKeySecret: "secret"},
+			},
+		},
+		{
+			name: "partial spaces keys",
+			environ: []string{
+				"DIGITALOCEAN_TOKEN_PAYMENTS=payments-token",
+				"DIGITALOCEAN_SPACES_ACCESS_KEY_ID_PAYMENTS=id",
+				"DIGITALOCEAN_TOKEN_ANALYTICS=analytics-token",
+				"DIGITALOCEAN_SPACES_ACCESS_KEY_SECRET_ANALYTICS=secret",
+			},
+			accounts: []Account{
+				{Name: "analytics", AccessToken: "analytics-token", SpacesAccessKeySecret: "secret"},
+				{Name: "payments", AccessToken: "payments-token", SpacesAccessKeyID: "id"},
+			},
+		},
+		{
+			name:     "empty values",
+			environ:  []string{"DIGITALOCEAN_TOKEN_PAYMENTS=", "DIGITALOCEAN_SPACES_ACCESS_KEY_ID_PAYMENTS="},
+			accounts: []Account{},
+		},
+		{
+			name:    "spaces keys without token",
+			environ: []string{"DIGITALOCEAN_SPACES_ACCESS_KEY_ID_PAYMENTS=id", "DIGITALOCEAN_SPACES_ACCESS_KEY_SECRET_PAYMENTS=secret"},
+			err:     true,
+		},
+		{
+			name:    "duplicate names",
+			environ: []string{"DIGITALOCEAN_TOKEN_PAYMENTS=token", "DIGITALOCEAN_TOKEN_payments=other-token"},
+			err:     true,
+		},
+		{
+			name:    "duplicate default",
+			config:  Config{DigitalOceanToken: "token"},
+			environ: []string{"DIGITALOCEAN_TOKEN_DEFAULT=other-token"},
+			err:     true,
+		},
+		{
+			name:    "empty name",
+			environ: []string{"DIGITALOCEAN_TOKEN_=token"},
+			err:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			accounts, err := accountsFromEnv(tc.config, tc.environ)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got accounts %v", accounts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(accounts, tc.accounts) {
+				t.Errorf("expected accounts %v, got %v", tc.accounts, accounts)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/digitalocean/godo"
@@ -10,17 +11,41 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-// AccountCollector collects metrics about the account.
+// The godo version in use predates teams, so the account is requested directly.
+const accountPath = "v2/account"
+
+type accountTeam struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+type account struct {
+	godo.Account
+	Team *accountTeam `json:"team"`
+}
+
+type accountRoot struct {
+	Account *account `json:"account"`
+}
+
+// AccountCollector collects metrics about the account,
+// its limits and how much of them is used.
 type AccountCollector struct {
 	logger  log.Logger
 	errors  *prometheus.CounterVec
 	client  *godo.Client
 	timeout time.Duration
 
-	DropletLimit    *prometheus.Desc
-	FloatingIPLimit *prometheus.Desc
-	EmailVerified   *prometheus.Desc
-	Active          *prometheus.Desc
+	Info             *prometheus.Desc
+	DropletLimit     *prometheus.Desc
+	FloatingIPLimit  *prometheus.Desc
+	VolumeLimit      *prometheus.Desc
+	DropletUsage     *prometheus.Desc
+	FloatingIPUsage  *prometheus.Desc
+	VolumeUsage      *prometheus.Desc
+	LimitUtilization *prometheus.Desc
+	EmailVerified    *prometheus.Desc
+	Active           *prometheus.Desc
 }
 
 // NewAccountCollector returns a new AccountCollector.
@@ -33,6 +58,11 @@ func NewAccountCollector(logger log.Logger, errors *prometheus.CounterVec, clien
 		client:  client,
 		timeout: timeout,
 
+		Info: prometheus.NewDesc(
+			"digitalocean_account_info",
+			"A metric with a constant '1' value labeled by the account's and its team's uuid and the team's name",
+			[]string{"uuid", "team_uuid", "team_name"}, nil,
+		),
 		DropletLimit: prometheus.NewDesc(
 			"digitalocean_account_droplet_limit",
 			"The maximum number of droplet you can use",
@@ -43,6 +73,31 @@ func NewAccountCollector(logger log.Logger, errors *prometheus.CounterVec, clien
 			"The maximum number of floating ips you can use",
 			nil, nil,
 		),
+		VolumeLimit: prometheus.NewDesc(
+			"digitalocean_account_volume_limit",
+			"The maximum number of volumes you can use",
+			nil, nil,
+		),
+		DropletUsage: prometheus.NewDesc(
+			"digitalocean_account_droplet_usage",
+			"The number of droplets you use",
+			nil, nil,
+		),
+		FloatingIPUsage: prometheus.NewDesc(
+			"digitalocean_account_floating_ip_usage",
+			"The number of floating ips, now called reserved ips, you use",
+			nil, nil,
+		),
+		VolumeUsage: prometheus.NewDesc(
+			"digitalocean_account_volume_usage",
+			"The number of volumes you use",
+			nil, nil,
+		),
+		LimitUtilization: prometheus.NewDesc(
+			"digitalocean_account_limit_utilization_ratio",
+			"The ratio of the limit you use",
+			[]string{"limit"}, nil,
+		),
 		EmailVerified: prometheus.NewDesc(
 			"digitalocean_account_verified",
 			"1 if your email address was verified",
@@ -59,8 +114,14 @@ func NewAccountCollector(logger log.Logger, errors *prometheus.CounterVec, clien
 // Describe sends the super-set of all possible descriptors of metrics
 // collected by this Collector.
 func (c *AccountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
 	ch <- c.DropletLimit
 	ch <- c.FloatingIPLimit
+	ch <- c.VolumeLimit
+	ch <- c.DropletUsage
+	ch <- c.FloatingIPUsage
+	ch <- c.VolumeUsage
+	ch <- c.LimitUtilization
 	ch <- c.EmailVerified
 	ch <- c.Active
 }
@@ -69,7 +130,11 @@ func (c *AccountCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *AccountCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	acc, _, err := c.client.Account.Get(ctx)
+	root := new(accountRoot)
+	_, err := get(ctx, c.client, accountPath, root)
+	if err == nil && root.Account == nil {
+		err = fmt.Errorf("response has no account")
+	}
 	if err != nil {
 		c.errors.WithLabelValues("account").Add(1)
 		level.Warn(c.logger).Log(
@@ -78,6 +143,18 @@ func (c *AccountCollector) Collect(ch chan<- prometheus.Metric) {
 		)
 		return
 	}
+	acc := root.Account
+
+	var teamUUID, teamName string
+	if acc.Team != nil {
+		teamUUID, teamName = acc.Team.UUID, acc.Team.Name
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.Info,
+		prometheus.GaugeValue,
+		1,
+		acc.UUID, teamUUID, teamName,
+	)
 
 	ch <- prometheus.MustNewConstMetric(
 		c.DropletLimit,
@@ -89,6 +166,57 @@ func (c *AccountCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(acc.FloatingIPLimit),
 	)
+	ch <- prometheus.MustNewConstMetric(
+		c.VolumeLimit,
+		prometheus.GaugeValue,
+		float64(acc.VolumeLimit),
+	)
+
+	// Failing to count a resource only drops its usage, the limits are still reported.
+	for _, u := range []struct {
+		limit string
+		max   int
+		desc  *prometheus.Desc
+		count func(opt *godo.ListOptions) (*godo.Response, error)
+	}{
+		{"droplet", acc.DropletLimit, c.DropletUsage, func(opt *godo.ListOptions) (*godo.Response, error) {
+			_, resp, err := c.client.Droplets.List(ctx, opt)
+			return resp, err
+		}},
+		{"floating_ip", acc.FloatingIPLimit, c.FloatingIPUsage, func(opt *godo.ListOptions) (*godo.Response, error) {
+			return listPage(ctx, c.client, reservedIPv4Path, opt, new(reservedIPv4sRoot))
+		}},
+		{"volume", acc.VolumeLimit, c.VolumeUsage, func(opt *godo.ListOptions) (*godo.Response, error) {
+			_, resp, err := c.client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{ListOptions: opt})
+			return resp, err
+		}},
+	} {
+		usage, err := total(u.count)
+		if err != nil {
+			c.errors.WithLabelValues("account").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't count usage",
+				"limit", u.limit,
+				"err", err,
+			)
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			u.desc,
+			prometheus.GaugeValue,
+			float64(usage),
+		)
+		// Without a limit there's nothing to utilize.
+		if u.max > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.LimitUtilization,
+				prometheus.GaugeValue,
+				float64(usage)/float64(u.max),
+				u.limit,
+			)
+		}
+	}
 
 	var verified float64
 	if acc.EmailVerified {
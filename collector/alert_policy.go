@@ -0,0 +1,237 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dropletAlertPolicyPrefix is the prefix of the types of all alert policies on droplet metrics.
+const dropletAlertPolicyPrefix = "v1/insights/droplet/"
+
+// AlertPolicyCollector collects metrics about Monitoring alert policies
+// and which droplets with a tag no enabled policy covers.
+type AlertPolicyCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	tag     string
+	timeout time.Duration
+
+	Info              *prometheus.Desc
+	Enabled           *prometheus.Desc
+	Threshold         *prometheus.Desc
+	Window            *prometheus.Desc
+	Entities          *prometheus.Desc
+	Tags              *prometheus.Desc
+	UncoveredDroplets *prometheus.Desc
+	UncoveredDroplet  *prometheus.Desc
+}
+
+// NewAlertPolicyCollector returns a new AlertPolicyCollector.
+// Droplets with the tag are checked to be covered by an enabled alert policy.
+func NewAlertPolicyCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, tag string, timeout time.Duration) *AlertPolicyCollector {
+	errors.WithLabelValues("alert_policy").Add(0)
+
+	labels := []string{"uuid"}
+	return &AlertPolicyCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		tag:     tag,
+		timeout: timeout,
+
+		Info: prometheus.NewDesc(
+			"digitalocean_alert_policy_info",
+			"A metric with a constant '1' value labeled by the alert policy's description, type and comparison",
+			[]string{"uuid", "description", "type", "compare"}, nil,
+		),
+		Enabled: prometheus.NewDesc(
+			"digitalocean_alert_policy_enabled",
+			"If 1 the alert policy is enabled, 0 otherwise",
+			labels, nil,
+		),
+		Threshold: prometheus.NewDesc(
+			"digitalocean_alert_policy_threshold",
+			"The value the alert policy compares the metric to",
+			labels, nil,
+		),
+		Window: prometheus.NewDesc(
+			"digitalocean_alert_policy_window_seconds",
+			"The window the metric has to exceed the threshold for, in seconds",
+			labels, nil,
+		),
+		Entities: prometheus.NewDesc(
+			"digitalocean_alert_policy_entities",
+			"The number of resources the alert policy applies to directly",
+			labels, nil,
+		),
+		Tags: prometheus.NewDesc(
+			"digitalocean_alert_policy_tags",
+			"The number of tags the alert policy applies to",
+			labels, nil,
+		),
+		UncoveredDroplets: prometheus.NewDesc(
+			"digitalocean_alert_policy_uncovered_droplets",
+			"The number of droplets with the tag no enabled droplet alert policy applies to",
+			[]string{"tag"}, nil,
+		),
+		UncoveredDroplet: prometheus.NewDesc(
+			"digitalocean_alert_policy_uncovered_droplet",
+			"A metric with a constant '1' value for every droplet with the tag no enabled droplet alert policy applies to",
+			[]string{"id", "name", "region", "tag"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *AlertPolicyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.Enabled
+	ch <- c.Threshold
+	ch <- c.Window
+	ch <- c.Entities
+	ch <- c.Tags
+	ch <- c.UncoveredDroplets
+	ch <- c.UncoveredDroplet
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *AlertPolicyCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	policies := []godo.AlertPolicy{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Monitoring.ListAlertPolicies(ctx, opt)
+		policies = append(policies, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("alert_policy").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list alert policies",
+			"err", err,
+		)
+		return
+	}
+
+	for _, policy := range policies {
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			policy.UUID, policy.Description, policy.Type, string(policy.Compare),
+		)
+
+		enabled := 0.0
+		if policy.Enabled {
+			enabled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Enabled,
+			prometheus.GaugeValue,
+			enabled,
+			policy.UUID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Threshold,
+			prometheus.GaugeValue,
+			float64(policy.Value),
+			policy.UUID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Entities,
+			prometheus.GaugeValue,
+			float64(len(policy.Entities)),
+			policy.UUID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Tags,
+			prometheus.GaugeValue,
+			float64(len(policy.Tags)),
+			policy.UUID,
+		)
+
+		window, err := time.ParseDuration(policy.Window)
+		if err != nil {
+			c.errors.WithLabelValues("alert_policy").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't parse alert policy window",
+				"policy", policy.UUID,
+				"err", err,
+			)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Window,
+			prometheus.GaugeValue,
+			window.Seconds(),
+			policy.UUID,
+		)
+	}
+
+	if c.tag == "" {
+		return
+	}
+
+	droplets, err := listDroplets(ctx, c.client)
+	if err != nil {
+		c.errors.WithLabelValues("alert_policy").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list droplets",
+			"err", err,
+		)
+		return
+	}
+
+	uncovered := 0
+	for _, droplet := range droplets {
+		if !(DropletFilter{Tag: c.tag}).matches(droplet) || coveredByAlertPolicy(droplet, policies) {
+			continue
+		}
+		uncovered++
+
+		ch <- prometheus.MustNewConstMetric(
+			c.UncoveredDroplet,
+			prometheus.GaugeValue,
+			1.0,
+			fmt.Sprintf("%d", droplet.ID), droplet.Name, droplet.Region.Slug, c.tag,
+		)
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.UncoveredDroplets,
+		prometheus.GaugeValue,
+		float64(uncovered),
+		c.tag,
+	)
+}
+
+// coveredByAlertPolicy returns whether any enabled droplet alert policy
+// applies to the droplet, either directly or by one of its tags.
+func coveredByAlertPolicy(droplet godo.Droplet, policies []godo.AlertPolicy) bool {
+	id := fmt.Sprintf("%d", droplet.ID)
+	for _, policy := range policies {
+		if !policy.Enabled || !strings.HasPrefix(policy.Type, dropletAlertPolicyPrefix) {
+			continue
+		}
+		for _, entity := range policy.Entities {
+			if entity == id {
+				return true
+			}
+		}
+		for _, tag := range policy.Tags {
+			if (DropletFilter{Tag: tag}).matches(droplet) {
+				return true
+			}
+		}
+	}
+	return false
+}
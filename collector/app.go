@@ -50,42 +50,19 @@ func (c *AppCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	// create a list to hold our apps
-	apps := []godo.App{}
-
-	// create options. initially, these will be blank
-	opt := &godo.ListOptions{}
-
-	for {
-		appsPage, resp, err := c.client.Apps.List(ctx, opt)
-		if err != nil {
-			c.errors.WithLabelValues("app").Add(1)
-			level.Warn(c.logger).Log(
-				"msg", "can't list apps",
-				"err", err,
-			)
-		}
-
-		// append the current page's apps to our list
-		for _, a := range appsPage {
-			apps = append(apps, *a)
-		}
-
-		// if we are at the last page, break out the for loop
-		if resp.Links == nil || resp.Links.IsLastPage() {
-			break
-		}
-
-		page, err := resp.Links.CurrentPage()
-		if err != nil {
-			c.errors.WithLabelValues("app").Add(1)
-			level.Warn(c.logger).Log(
-				"msg", "can't read current page",
-				"err", err,
-			)
-		}
-
-		opt.Page = page + 1
+	apps := []*godo.App{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Apps.List(ctx, opt)
+		apps = append(apps, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("app").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list apps",
+			"err", err,
+		)
+		return
 	}
 
 	for _, app := range apps {
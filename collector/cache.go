@@ -0,0 +1,161 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CachedCollector collects the metrics of another collector in the background
+// and serves the most recent snapshot of them when being scraped.
+// A refresh failed if the collector counted any errors while refreshing,
+// failed refreshes keep serving the previous snapshot.
+type CachedCollector struct {
+	logger    log.Logger
+	name      string
+	collector prometheus.Collector
+	errors    prometheus.Counter
+	interval  time.Duration
+
+	mtx         sync.RWMutex
+	metrics     []prometheus.Metric
+	lastRefresh time.Time
+
+	LastRefresh *prometheus.Desc
+}
+
+// NewCachedCollector returns a new CachedCollector refreshing the given collector every interval.
+// errors has to be the counter the collector increments on errors.
+// The collector isn't refreshed until Run is called.
+func NewCachedCollector(logger log.Logger, name string, collector prometheus.Collector, errors prometheus.Counter, interval time.Duration) *CachedCollector {
+	return &CachedCollector{
+		logger:    logger,
+		name:      name,
+		collector: collector,
+		errors:    errors,
+		interval:  interval,
+
+		LastRefresh: prometheus.NewDesc(
+			"digitalocean_collector_last_refresh_timestamp_seconds",
+			"Unix timestamp of the last time the collector's metrics were refreshed",
+			nil, prometheus.Labels{"collector": name},
+		),
+	}
+}
+
+// Run refreshes the metrics right away and then every interval until the context is canceled.
+func (c *CachedCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// instrumentation is implemented by collectors reporting on their own collection,
+// like the InstrumentedCollector. Their instrumentation is fresh even if collecting failed.
+type instrumentation interface {
+	instruments(desc *prometheus.Desc) bool
+}
+
+func (c *CachedCollector) refresh() {
+	start := time.Now()
+	errorsBefore := counterValue(c.errors)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.collector.Collect(ch)
+		close(ch)
+	}()
+
+	metrics := []prometheus.Metric{}
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+
+	if counterValue(c.errors) != errorsBefore {
+		c.mtx.Lock()
+		c.metrics = c.replaceInstrumentation(c.metrics, metrics)
+		c.mtx.Unlock()
+
+		level.Warn(c.logger).Log(
+			"msg", "can't refresh collector, serving previous metrics",
+			"collector", c.name,
+			"duration", time.Since(start),
+		)
+		return
+	}
+
+	c.mtx.Lock()
+	c.metrics = metrics
+	c.lastRefresh = time.Now()
+	c.mtx.Unlock()
+
+	level.Debug(c.logger).Log(
+		"msg", "refreshed collector",
+		"collector", c.name,
+		"metrics", len(metrics),
+		"duration", time.Since(start),
+	)
+}
+
+// replaceInstrumentation replaces the instrumentation metrics of the previous snapshot
+// with those of the failed refresh, so the failure is reported while the other metrics are kept.
+func (c *CachedCollector) replaceInstrumentation(previous, failed []prometheus.Metric) []prometheus.Metric {
+	inst, ok := c.collector.(instrumentation)
+	if !ok {
+		return previous
+	}
+
+	metrics := make([]prometheus.Metric, 0, len(previous))
+	for _, m := range previous {
+		if !inst.instruments(m.Desc()) {
+			metrics = append(metrics, m)
+		}
+	}
+	for _, m := range failed {
+		if inst.instruments(m.Desc()) {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *CachedCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.collector.Describe(ch)
+	ch <- c.LastRefresh
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CachedCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	// Refreshes failing since the start only have their instrumentation,
+	// so the failure is reported even without any metrics to serve.
+	for _, m := range c.metrics {
+		ch <- m
+	}
+
+	if c.lastRefresh.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.LastRefresh,
+		prometheus.GaugeValue,
+		float64(c.lastRefresh.Unix()),
+	)
+}
@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// testCollector collects a single gauge with its value,
+// counting an error instead if it's set to fail.
+type testCollector struct {
+	desc   *prometheus.Desc
+	errors prometheus.Counter
+
+	mtx   sync.Mutex
+	value float64
+	fail  bool
+}
+
+func newTestCollector(errors prometheus.Counter, value float64) *testCollector {
+	return &testCollector{
+		desc:   prometheus.NewDesc("test_value", "Test value", nil, nil),
+		errors: errors,
+		value:  value,
+	}
+}
+
+func (c *testCollector) set(value float64, fail bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.value, c.fail = value, fail
+}
+
+func (c *testCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *testCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.fail {
+		c.errors.Inc()
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, c.value)
+}
+
+// gatherValues returns the value of every gathered metric by its name.
+// Only use it with collectors having a single series per metric.
+func gatherValues(t *testing.T, c prometheus.Collector) map[string]float64 {
+	t.Helper()
+
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(c)
+	mfs, err := r.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]float64{}
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			values[mf.GetName()] = metricValue(m)
+		}
+	}
+	return values
+}
+
+func metricValue(m *dto.Metric) float64 {
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+	return m.GetGauge().GetValue()
+}
+
+func TestCachedCollectorRun(t *testing.T) {
+	errors := newErrors().WithLabelValues("test")
+	inner := newTestCollector(errors, 1)
+	c := NewCachedCollector(log.NewNopLogger(), "test", inner, errors, 10*time.Millisecond)
+
+	if values := gatherValues(t, c); len(values) != 0 {
+		t.Fatalf("expected no metrics before the first refresh, got %v", values)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.Run(ctx)
+
+	// Run refreshes right away and then every interval.
+	deadline := time.Now().Add(5 * time.Second)
+	for _, want := range []float64{1, 2, 3} {
+		for gatherValues(t, c)["test_value"] != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected test_value to be refreshed to %v", want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		inner.set(want+1, false)
+	}
+}
+
+func TestCachedCollectorRefresh(t *testing.T) {
+	errors := newErrors().WithLabelValues("test")
+	inner := newTestCollector(errors, 1)
+	instrumented := NewInstrumentedCollector(log.NewNopLogger(), "test", inner, errors)
+	c := NewCachedCollector(log.NewNopLogger(), "test", instrumented, errors, time.Hour)
+
+	inner.set(1, true)
+	c.refresh()
+	values := gatherValues(t, c)
+	if success, ok := values["digitalocean_scrape_collector_success"]; !ok || success != 0 {
+		t.Errorf("expected a refresh failing since the start to report success 0, got %v", values)
+	}
+	if _, ok := values["test_value"]; ok {
+		t.Errorf("expected no test_value without a successful refresh, got %v", values)
+	}
+	if _, ok := values["digitalocean_collector_last_refresh_timestamp_seconds"]; ok {
+		t.Errorf("expected no last refresh timestamp without a successful refresh, got %v", values)
+	}
+
+	inner.set(1, false)
+	c.refresh()
+	values = gatherValues(t, c)
+	if values["test_value"] != 1 || values["digitalocean_scrape_collector_success"] != 1 {
+		t.Fatalf("expected the first refresh to succeed, got %v", values)
+	}
+	lastRefresh := values["digitalocean_collector_last_refresh_timestamp_seconds"]
+	if lastRefresh != float64(c.lastRefresh.Unix()) || lastRefresh == 0 {
+		t.Fatalf("expected the last refresh timestamp %d, got %v", c.lastRefresh.Unix(), lastRefresh)
+	}
+
+	// Pretend the refresh happened earlier, to tell whether the timestamp moves.
+	c.lastRefresh = c.lastRefresh.Add(-time.Minute)
+	previousRefresh := c.lastRefresh
+
+	inner.set(2, true)
+	c.refresh()
+	values = gatherValues(t, c)
+	if values["test_value"] != 1 {
+		t.Errorf("expected a failed refresh to keep the previous test_value 1, got %v", values["test_value"])
+	}
+	if values["digitalocean_scrape_collector_success"] != 0 {
+		t.Errorf("expected a failed refresh to report success 0, got %v", values["digitalocean_scrape_collector_success"])
+	}
+	if got := values["digitalocean_collector_last_refresh_timestamp_seconds"]; got != float64(previousRefresh.Unix()) {
+		t.Errorf("expected a failed refresh to keep the last refresh timestamp %d, got %v", previousRefresh.Unix(), got)
+	}
+
+	inner.set(3, false)
+	c.refresh()
+	values = gatherValues(t, c)
+	if values["test_value"] != 3 || values["digitalocean_scrape_collector_success"] != 1 {
+		t.Errorf("expected the next refresh to succeed again, got %v", values)
+	}
+	if got := values["digitalocean_collector_last_refresh_timestamp_seconds"]; got <= float64(previousRefresh.Unix()) {
+		t.Errorf("expected the last refresh timestamp to move past %d, got %v", previousRefresh.Unix(), got)
+	}
+}
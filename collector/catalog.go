@@ -0,0 +1,222 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CatalogCollector collects the droplet sizes and regions DigitalOcean offers.
+type CatalogCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	SizeVCPUs        *prometheus.Desc
+	SizeMemory       *prometheus.Desc
+	SizeDisk         *prometheus.Desc
+	SizeTransfer     *prometheus.Desc
+	SizePriceHourly  *prometheus.Desc
+	SizePriceMonthly *prometheus.Desc
+	SizeAvailable    *prometheus.Desc
+	RegionAvailable  *prometheus.Desc
+	RegionFeature    *prometheus.Desc
+}
+
+// NewCatalogCollector returns a new CatalogCollector.
+func NewCatalogCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *CatalogCollector {
+	errors.WithLabelValues("catalog").Add(0)
+
+	sizeLabels := []string{"size", "description"}
+	return &CatalogCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		SizeVCPUs: prometheus.NewDesc(
+			"digitalocean_size_cpus",
+			"The number of virtual CPUs of droplets of this size",
+			sizeLabels, nil,
+		),
+		SizeMemory: prometheus.NewDesc(
+			"digitalocean_size_memory_bytes",
+			"The memory of droplets of this size in bytes",
+			sizeLabels, nil,
+		),
+		SizeDisk: prometheus.NewDesc(
+			"digitalocean_size_disk_bytes",
+			"The disk of droplets of this size in bytes",
+			sizeLabels, nil,
+		),
+		SizeTransfer: prometheus.NewDesc(
+			"digitalocean_size_transfer_bytes",
+			"The monthly outbound transfer included with droplets of this size in bytes",
+			sizeLabels, nil,
+		),
+		SizePriceHourly: prometheus.NewDesc(
+			"digitalocean_size_price_hourly",
+			"The hourly price of droplets of this size in dollars",
+			sizeLabels, nil,
+		),
+		SizePriceMonthly: prometheus.NewDesc(
+			"digitalocean_size_price_monthly",
+			"The monthly price of droplets of this size in dollars",
+			sizeLabels, nil,
+		),
+		SizeAvailable: prometheus.NewDesc(
+			"digitalocean_size_available",
+			"If 1 droplets of this size can be created in the region, 0 otherwise",
+			[]string{"size", "region"}, nil,
+		),
+		RegionAvailable: prometheus.NewDesc(
+			"digitalocean_region_available",
+			"If 1 droplets can be created in the region, 0 otherwise",
+			[]string{"region", "name"}, nil,
+		),
+		RegionFeature: prometheus.NewDesc(
+			"digitalocean_region_feature",
+			"If 1 the region supports the feature, 0 if only other regions do",
+			[]string{"region", "feature"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *CatalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.SizeVCPUs
+	ch <- c.SizeMemory
+	ch <- c.SizeDisk
+	ch <- c.SizeTransfer
+	ch <- c.SizePriceHourly
+	ch <- c.SizePriceMonthly
+	ch <- c.SizeAvailable
+	ch <- c.RegionAvailable
+	ch <- c.RegionFeature
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CatalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	regions := []godo.Region{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Regions.List(ctx, opt)
+		regions = append(regions, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("catalog").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list regions",
+			"err", err,
+		)
+		return
+	}
+
+	sizes := []godo.Size{}
+	err = paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Sizes.List(ctx, opt)
+		sizes = append(sizes, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("catalog").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list sizes",
+			"err", err,
+		)
+		return
+	}
+
+	// Features any region supports are reported for every region,
+	// so a region losing a feature turns its metric to 0 instead of disappearing.
+	features := map[string]bool{}
+	for _, region := range regions {
+		for _, feature := range region.Features {
+			features[feature] = true
+		}
+	}
+
+	available := map[string]bool{}
+	for _, region := range regions {
+		available[region.Slug] = region.Available
+
+		regionAvailable := 0.0
+		if region.Available {
+			regionAvailable = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.RegionAvailable,
+			prometheus.GaugeValue,
+			regionAvailable,
+			region.Slug, region.Name,
+		)
+
+		supported := map[string]bool{}
+		for _, feature := range region.Features {
+			supported[feature] = true
+		}
+		for feature := range features {
+			value := 0.0
+			if supported[feature] {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.RegionFeature,
+				prometheus.GaugeValue,
+				value,
+				region.Slug, feature,
+			)
+		}
+	}
+
+	for _, size := range sizes {
+		labels := []string{size.Slug, size.Description}
+
+		for _, m := range []struct {
+			desc  *prometheus.Desc
+			value float64
+		}{
+			{c.SizeVCPUs, float64(size.Vcpus)},
+			{c.SizeMemory, float64(size.Memory * 1024 * 1024)},
+			{c.SizeDisk, float64(size.Disk * 1000 * 1000 * 1000)},
+			{c.SizeTransfer, size.Transfer * 1000 * 1000 * 1000 * 1000},
+			{c.SizePriceHourly, size.PriceHourly},
+			{c.SizePriceMonthly, size.PriceMonthly},
+		} {
+			ch <- prometheus.MustNewConstMetric(
+				m.desc,
+				prometheus.GaugeValue,
+				m.value,
+				labels...,
+			)
+		}
+
+		// Every size is reported for every region, so a size no longer
+		// offered in a region turns its metric to 0 instead of disappearing.
+		offered := map[string]bool{}
+		for _, region := range size.Regions {
+			offered[region] = true
+		}
+		for _, region := range regions {
+			value := 0.0
+			if size.Available && available[region.Slug] && offered[region.Slug] {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				c.SizeAvailable,
+				prometheus.GaugeValue,
+				value,
+				size.Slug, region.Slug,
+			)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CDNCollector collects metrics about CDN endpoints of that account.
+// With Spaces keys it also checks that the origin bucket of every endpoint still exists.
+type CDNCollector struct {
+	spacesLister
+
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	Info          *prometheus.Desc
+	TTL           *prometheus.Desc
+	OriginMissing *prometheus.Desc
+}
+
+// NewCDNCollector returns a new CDNCollector.
+// If the Spaces access key id and secret are empty, the origins aren't checked.
+func NewCDNCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, accessKeyID string, accessKeySecret string, timeout time.Duration) *CDNCollector {
+	errors.WithLabelValues("cdn").Add(0)
+
+	return &CDNCollector{
+		spacesLister: newSpacesLister(client, accessKeyID, accessKeySecret),
+		logger:       logger,
+		errors:       errors,
+		client:       client,
+		timeout:      timeout,
+
+		Info: prometheus.NewDesc(
+			"digitalocean_cdn_endpoint_info",
+			"A metric with a constant '1' value labeled by the CDN endpoint's origin, hostname, custom domain and certificate",
+			[]string{"id", "origin", "endpoint", "custom_domain", "certificate_id"}, nil,
+		),
+		TTL: prometheus.NewDesc(
+			"digitalocean_cdn_endpoint_ttl_seconds",
+			"Seconds the CDN endpoint caches its content",
+			[]string{"id", "endpoint"}, nil,
+		),
+		OriginMissing: prometheus.NewDesc(
+			"digitalocean_cdn_endpoint_origin_missing",
+			"If 1 the CDN endpoint's origin bucket doesn't exist anymore, 0 otherwise",
+			[]string{"id", "endpoint", "origin"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *CDNCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.TTL
+	ch <- c.OriginMissing
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CDNCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	endpoints := []godo.CDN{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.CDNs.List(ctx, opt)
+		endpoints = append(endpoints, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("cdn").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list cdn endpoints",
+			"err", err,
+		)
+		return
+	}
+
+	for _, cdn := range endpoints {
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			cdn.ID, cdn.Origin, cdn.Endpoint, cdn.CustomDomain, cdn.CertificateID,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.TTL,
+			prometheus.GaugeValue,
+			float64(cdn.TTL),
+			cdn.ID, cdn.Endpoint,
+		)
+	}
+
+	if c.accessKeyID == "" || c.accessKeySecret == "" || len(endpoints) == 0 {
+		return
+	}
+
+	buckets, errs := c.listBuckets(ctx)
+	if len(errs) > 0 {
+		// Without the buckets of every region, existing origins could be reported missing.
+		for _, err := range errs {
+			c.errors.WithLabelValues("cdn").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't list spaces buckets to check cdn origins",
+				"err", err,
+			)
+		}
+		return
+	}
+
+	origins := make(map[string]bool, len(buckets))
+	for _, bucket := range buckets {
+		origins[bucket.Name+"."+spacesEndpoint(bucket.Region)] = true
+	}
+
+	for _, cdn := range endpoints {
+		missing := 0.0
+		if !origins[cdn.Origin] {
+			missing = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.OriginMissing,
+			prometheus.GaugeValue,
+			missing,
+			cdn.ID, cdn.Endpoint, cdn.Origin,
+		)
+	}
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// certificateStates are all states a certificate can be in.
+var certificateStates = []string{"pending", "verified", "error"}
+
+// CertificateCollector collects metrics about TLS certificates of that account.
+type CertificateCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	NotAfter *prometheus.Desc
+	State    *prometheus.Desc
+}
+
+// NewCertificateCollector returns a new CertificateCollector.
+func NewCertificateCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *CertificateCollector {
+	errors.WithLabelValues("certificate").Add(0)
+
+	return &CertificateCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		NotAfter: prometheus.NewDesc(
+			"digitalocean_certificate_not_after_timestamp_seconds",
+			"Unix timestamp when the certificate expires",
+			[]string{"id", "name", "type", "dns_names"}, nil,
+		),
+		State: prometheus.NewDesc(
+			"digitalocean_certificate_state",
+			"The state of the certificate, 1 for the current state, 0 for all others",
+			[]string{"id", "name", "type", "state"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *CertificateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.NotAfter
+	ch <- c.State
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CertificateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	certs := []godo.Certificate{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Certificates.List(ctx, opt)
+		certs = append(certs, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("certificate").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list certificates",
+			"err", err,
+		)
+		return
+	}
+
+	for _, cert := range certs {
+		stateMetrics(ch, c.State, certificateStates, cert.State, cert.ID, cert.Name, cert.Type)
+
+		// Pending Let's Encrypt certificates aren't issued yet and don't expire.
+		if cert.NotAfter == "" {
+			continue
+		}
+		notAfter, err := time.Parse(time.RFC3339, cert.NotAfter)
+		if err != nil {
+			c.errors.WithLabelValues("certificate").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't parse certificate expiry time",
+				"certificate", cert.ID,
+				"err", err,
+			)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.NotAfter,
+			prometheus.GaugeValue,
+			float64(notAfter.Unix()),
+			cert.ID, cert.Name, cert.Type, strings.Join(cert.DNSNames, ","),
+		)
+	}
+}
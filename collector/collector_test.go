@@ -0,0 +1,575 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/metalmatze/digitalocean_exporter/collector/fake"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func newErrors() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "digitalocean_errors_total",
+		Help: "The total number of errors per collector",
+	}, []string{"collector"})
+}
+
+func newFakeServer(t *testing.T) *fake.Server {
+	s := fake.NewServer(filepath.Join("testdata", "fixtures"))
+	t.Cleanup(s.Close)
+	// Make sure the fixtures span multiple pages.
+	s.SetPerPage(2)
+	return s
+}
+
+func TestCollectors(t *testing.T) {
+	logger := log.NewNopLogger()
+	timeout := 5 * time.Second
+
+	for _, tc := range []struct {
+		name      string
+		setup     func(s *fake.Server)
+		collector func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector
+	}{
+		{
+			name: "account",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewAccountCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "account_usage_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/volumes", http.StatusInternalServerError, "Server Error")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewAccountCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "alert_policy",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewAlertPolicyCollector(logger, errors, s.GodoClient(), "prod", timeout)
+			},
+		},
+		{
+			name: "app",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewAppCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "balance",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewBalanceCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "catalog",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewCatalogCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "cdn",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				c := NewCDNCollector(logger, errors, s.GodoClient(), "access-key-id", "access-key-secret", timeout)
+				c.transport = s.SpacesTransport()
+				c.secure = false
+				return c
+			},
+		},
+		{
+			name: "cdn_without_spaces_keys",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewCDNCollector(logger, errors, s.GodoClient(), "", "", timeout)
+			},
+		},
+		{
+			name: "certificate",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewCertificateCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "cost",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				c := NewCostCollector(logger, errors, s.GodoClient(), "access-key-id", "access-key-secret", DefaultPrices(), timeout)
+				c.transport = s.SpacesTransport()
+				c.secure = false
+				return c
+			},
+		},
+		{
+			name: "cost_sizes_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/sizes", http.StatusInternalServerError, "Server Error")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewCostCollector(logger, errors, s.GodoClient(), "", "", DefaultPrices(), timeout)
+			},
+		},
+		{
+			name: "database",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDBCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "domain",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDomainCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "domain_records_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/domains/example.com/records", http.StatusForbidden, "You are not authorized to perform this operation")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDomainCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "droplet",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDropletCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "droplet_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/droplets", http.StatusInternalServerError, "Server Error")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDropletCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "droplet_backup",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDropletBackupCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "droplet_backup_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/droplets/3164444/backups", http.StatusInternalServerError, "Server Error")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDropletBackupCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "droplet_utilization",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDropletUtilizationCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "droplet_utilization_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/monitoring/metrics/droplet/load_5", http.StatusInternalServerError, "Server Error")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewDropletUtilizationCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "firewall",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewFirewallCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "floating_ip",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewFloatingIPCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "image",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewImageCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "incidents",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				c := NewIncidentCollector(logger, errors, timeout)
+				c.statusURL = s.URL + "/api/v2/summary.json"
+				return c
+			},
+		},
+		{
+			name: "invoice",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewInvoiceCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "invoice_summary_error",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/customers/my/invoices/22737452-c7e1-4bd3-86f6-000000000001/summary", http.StatusInternalServerError, "Server Error")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewInvoiceCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "key",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewKeyCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "kubernetes",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewKubernetesCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "loadbalancer",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewLoadBalancerCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "project",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewProjectCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "registry",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewRegistryCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "registry_not_found",
+			setup: func(s *fake.Server) {
+				s.Fail("/v2/registry", http.StatusNotFound, "The resource you were accessing could not be found.")
+			},
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewRegistryCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "reserved_ip",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewReservedIPCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "snapshot",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewSnapshotCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "spaces",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				c := NewSpacesCollector(logger, errors, s.GodoClient(), "access-key-id", "access-key-secret", timeout)
+				c.transport = s.SpacesTransport()
+				c.secure = false
+				return c
+			},
+		},
+		{
+			name: "tag",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewTagCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "uptime",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewUptimeCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "volume",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewVolumeCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+		{
+			name: "vpc",
+			collector: func(s *fake.Server, errors *prometheus.CounterVec) prometheus.Collector {
+				return NewVPCCollector(logger, errors, s.GodoClient(), timeout)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newFakeServer(t)
+			if tc.setup != nil {
+				tc.setup(s)
+			}
+
+			errors := newErrors()
+			r := prometheus.NewPedanticRegistry()
+			r.MustRegister(tc.collector(s, errors))
+			// The registry collects concurrently, gather the errors afterwards
+			// to make sure all errors of the collector are counted.
+			er := prometheus.NewPedanticRegistry()
+			er.MustRegister(errors)
+
+			compareGolden(t, tc.name, gather(t, prometheus.Gatherers{r, er}))
+		})
+	}
+}
+
+func TestReservedIPReassignments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.Mkdir(filepath.Join(dir, "v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	assign := func(dropletID int) {
+		droplet := "null"
+		if dropletID != 0 {
+			droplet = fmt.Sprintf(`{"id": %d, "name": "web-%d"}`, dropletID, dropletID)
+		}
+		fixtures := map[string]string{
+			"reserved_ips.json":  fmt.Sprintf(`{"reserved_ips": [{"ip": "45.55.96.47", "droplet": %s, "region": {"slug": "nyc3"}}]}`, droplet),
+			"reserved_ipv6.json": `{"reserved_ipv6s": []}`,
+		}
+		for name, content := range fixtures {
+			if err := ioutil.WriteFile(filepath.Join(dir, "v2", name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	s := fake.NewServer(dir)
+	t.Cleanup(s.Close)
+
+	errors := newErrors()
+	c := NewReservedIPCollector(log.NewNopLogger(), errors, s.GodoClient(), 5*time.Second)
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(c)
+
+	// Droplet 0 means the IP is unassigned.
+	for _, step := range []struct {
+		dropletID     int
+		reassignments float64
+	}{
+		// New and unassigned.
+		{dropletID: 0, reassignments: 0},
+		// Unassigned to a droplet.
+		{dropletID: 1, reassignments: 0},
+		// The same droplet.
+		{dropletID: 1, reassignments: 0},
+		// One droplet to another.
+		{dropletID: 2, reassignments: 1},
+		// A droplet to unassigned.
+		{dropletID: 0, reassignments: 1},
+		// Unassigned back to the same droplet.
+		{dropletID: 2, reassignments: 1},
+		{dropletID: 0, reassignments: 1},
+		// Unassigned to another droplet than the last one.
+		{dropletID: 1, reassignments: 2},
+		{dropletID: 3, reassignments: 3},
+	} {
+		assign(step.dropletID)
+		if _, err := r.Gather(); err != nil {
+			t.Fatal(err)
+		}
+		if got := testutil.ToFloat64(c.Reassignments.WithLabelValues("45.55.96.47", "ipv4")); got != step.reassignments {
+			t.Errorf("expected %v reassignments after assigning droplet %d, got %v", step.reassignments, step.dropletID, got)
+		}
+	}
+	if got := testutil.ToFloat64(errors); got != 0 {
+		t.Errorf("expected no errors, got %v", got)
+	}
+}
+
+func TestRateLimitCollector(t *testing.T) {
+	s := newFakeServer(t)
+	s.SetRateLimit(5000, 4998, time.Unix(1600000000, 0))
+
+	c := NewRateLimitCollector()
+	client := s.GodoClient()
+	client.OnRequestCompleted(c.Observe)
+
+	if _, _, err := client.Account.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.Fail("/v2/account", http.StatusTooManyRequests, "Too many requests")
+	if _, _, err := client.Account.Get(context.Background()); err == nil {
+		t.Fatal("expected rate limited request to fail")
+	}
+
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(c)
+
+	compareGolden(t, "ratelimit", gather(t, r))
+}
+
+func TestDropletDiscovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.Mkdir(filepath.Join(dir, "v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Next to the shared droplets, a VPC-only droplet is addressed by its private IPv4
+	// and a droplet without any IPv4 is skipped.
+	content, err := ioutil.ReadFile(filepath.Join("testdata", "fixtures", "v2", "droplets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		Droplets []json.RawMessage `json:"droplets"`
+	}
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		t.Fatal(err)
+	}
+	fixture.Droplets = append(fixture.Droplets,
+		json.RawMessage(`{"id": 3164470, "name": "db-1", "status": "active", "size_slug": "s-2vcpu-4gb", "region": {"slug": "nyc3"}, "tags": ["prod"],
+			"networks": {"v4": [{"ip_address": "10.128.192.130", "type": "private"}]}, "vpc_uuid": "760e09ef-dc84-11e8-981e-3cfdfeaae000"}`),
+		json.RawMessage(`{"id": 3164480, "name": "builder-1", "status": "active", "size_slug": "s-2vcpu-4gb", "region": {"slug": "nyc3"}, "tags": ["prod"],
+			"networks": {"v4": []}}`),
+	)
+	content, err = json.Marshal(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "v2", "droplets.json"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := fake.NewServer(dir)
+	t.Cleanup(s.Close)
+	s.SetPerPage(2)
+
+	d := NewDropletDiscovery(log.NewNopLogger(), 5*time.Second, 9100)
+	d.AddAccount("default", newErrors(), s.GodoClient())
+	filter := DropletFilter{Tag: "prod", Region: "nyc3"}
+
+	groups, err := d.TargetGroups(context.Background(), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err = json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "droplet_sd", content)
+
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sd/droplets?tag=prod&region=nyc3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var served []TargetGroup
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(served, groups) {
+		t.Errorf("expected the served targets to equal the target groups\n\ngot:\n%v\nwant:\n%v", served, groups)
+	}
+
+	path := filepath.Join(dir, "droplets.json")
+	if err := d.WriteFile(context.Background(), path, filter); err != nil {
+		t.Fatal(err)
+	}
+	written, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareGolden(t, "droplet_sd", written)
+}
+
+func TestLoadPrices(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "prices.json")
+	content := `{"volume_gigabyte": 0.12, "database_sizes": {"db-s-custom": 42}}`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	prices, err := LoadPrices(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prices.VolumeGigabyte != 0.12 {
+		t.Errorf("expected volume price of 0.12, got %v", prices.VolumeGigabyte)
+	}
+	if prices.SnapshotGigabyte != DefaultPrices().SnapshotGigabyte {
+		t.Errorf("expected default snapshot price, got %v", prices.SnapshotGigabyte)
+	}
+	if prices.DatabaseSizes["db-s-custom"] != 42 || prices.DatabaseSizes["db-s-1vcpu-1gb"] != 15 {
+		t.Errorf("expected custom and default database sizes, got %v", prices.DatabaseSizes)
+	}
+}
+
+// gather returns all metrics of the gatherer in the text exposition format.
+func gather(t *testing.T, g prometheus.Gatherer) []byte {
+	t.Helper()
+
+	mfs, err := g.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	enc := expfmt.NewEncoder(&buf, expfmt.FmtText)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// compareGolden compares got with the content of testdata/<name>.golden,
+// updating the file instead if the -update flag is set.
+func compareGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test -update to update it\n\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
@@ -0,0 +1,316 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CostCollector computes the monthly cost of all billable resources.
+// Droplets and Kubernetes nodes are priced by their size,
+// all other resources by the per-unit prices of the price table.
+type CostCollector struct {
+	spacesLister
+
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	prices  Prices
+	timeout time.Duration
+
+	CostMonthly *prometheus.Desc
+}
+
+// NewCostCollector returns a new CostCollector.
+// The Spaces subscription is only included if the spaces keys are set.
+func NewCostCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, accessKeyID string, accessKeySecret string, prices Prices, timeout time.Duration) *CostCollector {
+	errors.WithLabelValues("cost").Add(0)
+
+	return &CostCollector{
+		spacesLister: newSpacesLister(client, accessKeyID, accessKeySecret),
+		logger:       logger,
+		errors:       errors,
+		prices:       prices,
+		timeout:      timeout,
+
+		CostMonthly: prometheus.NewDesc(
+			"digitalocean_resource_cost_monthly_dollars",
+			"The resource's computed monthly cost in dollars",
+			[]string{"type", "id", "name"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *CostCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CostMonthly
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CostCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	sizes, err := c.listSizes(ctx)
+	if err != nil {
+		// Droplets still know their size's price, only node pools can't be priced.
+		c.errors.WithLabelValues("cost").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list sizes",
+			"err", err,
+		)
+	}
+
+	// A resource type failing doesn't keep the others from being priced.
+	for _, r := range []struct {
+		name    string
+		collect func(context.Context, chan<- prometheus.Metric, map[string]godo.Size) error
+	}{
+		{"droplets", c.collectDroplets},
+		{"kubernetes clusters", c.collectNodePools},
+		{"volumes", c.collectVolumes},
+		{"snapshots", c.collectSnapshots},
+		{"load balancers", c.collectLoadBalancers},
+		{"reserved ips", c.collectReservedIPs},
+		{"database clusters", c.collectDatabases},
+		{"spaces buckets", c.collectSpaces},
+	} {
+		if err := r.collect(ctx, ch, sizes); err != nil {
+			c.errors.WithLabelValues("cost").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't price "+r.name,
+				"err", err,
+			)
+		}
+	}
+}
+
+func (c *CostCollector) cost(ch chan<- prometheus.Metric, value float64, typ, id, name string) {
+	ch <- prometheus.MustNewConstMetric(
+		c.CostMonthly,
+		prometheus.GaugeValue,
+		value,
+		typ, id, name,
+	)
+}
+
+// unknownSize logs resources that can't be priced, as the price table lacks their size.
+func (c *CostCollector) unknownSize(typ, id, size string) {
+	level.Warn(c.logger).Log(
+		"msg", "no price for size, add it to the price table",
+		"type", typ,
+		"id", id,
+		"size", size,
+	)
+}
+
+// listSizes returns all sizes by their slug.
+func (c *CostCollector) listSizes(ctx context.Context) (map[string]godo.Size, error) {
+	sizes := map[string]godo.Size{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Sizes.List(ctx, opt)
+		for _, size := range page {
+			sizes[size.Slug] = size
+		}
+		return resp, err
+	})
+	return sizes, err
+}
+
+func (c *CostCollector) collectDroplets(ctx context.Context, ch chan<- prometheus.Metric, sizes map[string]godo.Size) error {
+	droplets, err := listDroplets(ctx, c.client)
+	if err != nil {
+		return err
+	}
+
+	for _, droplet := range droplets {
+		// Kubernetes worker nodes are droplets tagged k8s, they are priced with their node pool.
+		if isKubernetesNode(droplet) {
+			continue
+		}
+
+		price := 0.0
+		if size, ok := sizes[droplet.SizeSlug]; ok {
+			price = size.PriceMonthly
+		} else if droplet.Size != nil {
+			price = droplet.Size.PriceMonthly
+		}
+
+		for _, feature := range droplet.Features {
+			if feature == "backups" {
+				price += price * c.prices.DropletBackups
+			}
+		}
+
+		c.cost(ch, price, "droplet", fmt.Sprintf("%d", droplet.ID), droplet.Name)
+	}
+
+	return nil
+}
+
+// isKubernetesNode returns whether the droplet is a worker node of a Kubernetes cluster.
+func isKubernetesNode(droplet godo.Droplet) bool {
+	for _, tag := range droplet.Tags {
+		if tag == "k8s" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CostCollector) collectNodePools(ctx context.Context, ch chan<- prometheus.Metric, sizes map[string]godo.Size) error {
+	clusters := []*godo.KubernetesCluster{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Kubernetes.List(ctx, opt)
+		clusters = append(clusters, page...)
+		return resp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range clusters {
+		for _, pool := range cluster.NodePools {
+			size, ok := sizes[pool.Size]
+			if !ok {
+				c.unknownSize("kubernetes_node_pool", pool.ID, pool.Size)
+				continue
+			}
+			// Pool names are only unique within their cluster.
+			c.cost(ch, size.PriceMonthly*float64(pool.Count), "kubernetes_node_pool", pool.ID, cluster.Name+"/"+pool.Name)
+		}
+	}
+
+	return nil
+}
+
+func (c *CostCollector) collectVolumes(ctx context.Context, ch chan<- prometheus.Metric, _ map[string]godo.Size) error {
+	volumes := []godo.Volume{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{ListOptions: opt})
+		volumes = append(volumes, page...)
+		return resp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		c.cost(ch, float64(volume.SizeGigaBytes)*c.prices.VolumeGigabyte, "volume", volume.ID, volume.Name)
+	}
+
+	return nil
+}
+
+func (c *CostCollector) collectSnapshots(ctx context.Context, ch chan<- prometheus.Metric, _ map[string]godo.Size) error {
+	snapshots := []godo.Snapshot{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Snapshots.List(ctx, opt)
+		snapshots = append(snapshots, page...)
+		return resp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		c.cost(ch, snapshot.SizeGigaBytes*c.prices.SnapshotGigabyte, "snapshot", snapshot.ID, snapshot.Name)
+	}
+
+	return nil
+}
+
+func (c *CostCollector) collectLoadBalancers(ctx context.Context, ch chan<- prometheus.Metric, _ map[string]godo.Size) error {
+	lbs := []godo.LoadBalancer{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.LoadBalancers.List(ctx, opt)
+		lbs = append(lbs, page...)
+		return resp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, lb := range lbs {
+		// Newer load balancers are scaled by nodes instead of having a fixed size.
+		if lb.SizeUnit > 0 {
+			c.cost(ch, float64(lb.SizeUnit)*c.prices.LoadBalancerNode, "load_balancer", lb.ID, lb.Name)
+			continue
+		}
+		price, ok := c.prices.LoadBalancerSizes[lb.SizeSlug]
+		if !ok {
+			c.unknownSize("load_balancer", lb.ID, lb.SizeSlug)
+			continue
+		}
+		c.cost(ch, price, "load_balancer", lb.ID, lb.Name)
+	}
+
+	return nil
+}
+
+func (c *CostCollector) collectReservedIPs(ctx context.Context, ch chan<- prometheus.Metric, _ map[string]godo.Size) error {
+	ips, err := listReservedIPs(ctx, c.client)
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range ips {
+		// Reserved IPv6 addresses are free.
+		if ip.family != "ipv4" {
+			continue
+		}
+		price := 0.0
+		if ip.droplet == nil {
+			price = c.prices.ReservedIPv4
+		}
+		c.cost(ch, price, "reserved_ip", ip.ip, ip.ip)
+	}
+
+	return nil
+}
+
+func (c *CostCollector) collectDatabases(ctx context.Context, ch chan<- prometheus.Metric, _ map[string]godo.Size) error {
+	dbs := []godo.Database{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Databases.List(ctx, opt)
+		dbs = append(dbs, page...)
+		return resp, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, db := range dbs {
+		price, ok := c.prices.DatabaseSizes[db.SizeSlug]
+		if !ok {
+			c.unknownSize("database", db.ID, db.SizeSlug)
+			continue
+		}
+		c.cost(ch, price*float64(db.NumNodes), "database", db.ID, db.Name)
+	}
+
+	return nil
+}
+
+func (c *CostCollector) collectSpaces(ctx context.Context, ch chan<- prometheus.Metric, _ map[string]godo.Size) error {
+	if c.accessKeyID == "" || c.accessKeySecret == "" {
+		return nil
+	}
+
+	buckets, errs := c.listBuckets(ctx)
+	if len(errs) > 0 {
+		return fmt.Errorf("can't list spaces buckets: %v", errs)
+	}
+	// The subscription includes all buckets, it's only paid once any exist.
+	if len(buckets) == 0 {
+		return nil
+	}
+	c.cost(ch, c.prices.SpacesSubscription, "spaces", "", "subscription")
+
+	return nil
+}
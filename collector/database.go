@@ -68,42 +68,19 @@ func (c *DBCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	// create a list to hold our dbs
 	dbs := []godo.Database{}
-
-	// create options. initially, these will be blank
-	opt := &godo.ListOptions{}
-
-	for {
-		dbPage, resp, err := c.client.Databases.List(ctx, opt)
-		if err != nil {
-			c.errors.WithLabelValues("database").Add(1)
-			level.Warn(c.logger).Log(
-				"msg", "can't list databases",
-				"err", err,
-			)
-		}
-
-		// append the current page's dbs to our list
-		for _, d := range dbPage {
-			dbs = append(dbs, d)
-		}
-
-		// if we are at the last page, break out the for loop
-		if resp.Links == nil || resp.Links.IsLastPage() {
-			break
-		}
-
-		page, err := resp.Links.CurrentPage()
-		if err != nil {
-			c.errors.WithLabelValues("database").Add(1)
-			level.Warn(c.logger).Log(
-				"msg", "can't read current page",
-				"err", err,
-			)
-		}
-
-		opt.Page = page + 1
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Databases.List(ctx, opt)
+		dbs = append(dbs, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("database").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list databases",
+			"err", err,
+		)
+		return
 	}
 
 	for _, db := range dbs {
@@ -72,7 +72,12 @@ func (c *DomainCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	domains, _, err := c.client.Domains.List(ctx, nil)
+	domains := []godo.Domain{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Domains.List(ctx, opt)
+		domains = append(domains, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("domain").Add(1)
 		level.Warn(c.logger).Log(
@@ -90,10 +95,17 @@ func (c *DomainCollector) Collect(ch chan<- prometheus.Metric) {
 			domain.Name,
 		)
 
-		//ctx, cancel := context.WithTimeout(ctx, c.timeout)
-		//cancel()
-		ctx := context.TODO()
-		records, _, _ := c.client.Domains.Records(ctx, domain.Name, nil)
+		records, err := c.listRecords(domain.Name)
+		if err != nil {
+			c.errors.WithLabelValues("domain").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't list domain records",
+				"domain", domain.Name,
+				"err", err,
+			)
+			continue
+		}
+
 		for _, record := range records {
 			ch <- prometheus.MustNewConstMetric(
 				c.DomainRecordPort,
@@ -116,3 +128,18 @@ func (c *DomainCollector) Collect(ch chan<- prometheus.Metric) {
 		}
 	}
 }
+
+// listRecords pages through all records of the domain.
+// Every domain gets its own timeout, as accounts can have a lot of domains.
+func (c *DomainCollector) listRecords(domain string) ([]godo.DomainRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	records := []godo.DomainRecord{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Domains.Records(ctx, domain, opt)
+		records = append(records, page...)
+		return resp, err
+	})
+	return records, err
+}
@@ -3,6 +3,7 @@ package collector
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/digitalocean/godo"
@@ -18,6 +19,8 @@ type DropletCollector struct {
 	client  *godo.Client
 	timeout time.Duration
 
+	Info         *prometheus.Desc
+	Created      *prometheus.Desc
 	Up           *prometheus.Desc
 	CPUs         *prometheus.Desc
 	Memory       *prometheus.Desc
@@ -37,6 +40,21 @@ func NewDropletCollector(logger log.Logger, errors *prometheus.CounterVec, clien
 		client:  client,
 		timeout: timeout,
 
+		Info: prometheus.NewDesc(
+			"digitalocean_droplet_info",
+			"A metric with a constant '1' value labeled by the droplet's size, image, networking, tags and features",
+			[]string{
+				"id", "name", "region",
+				"size_slug", "image_distribution", "image_slug", "vpc_uuid",
+				"public_ipv4", "private_ipv4", "public_ipv6",
+				"tags", "features",
+			}, nil,
+		),
+		Created: prometheus.NewDesc(
+			"digitalocean_droplet_created_timestamp_seconds",
+			"Unix timestamp of the droplet's creation",
+			labels, nil,
+		),
 		Up: prometheus.NewDesc(
 			"digitalocean_droplet_up",
 			"If 1 the droplet is up and running, 0 otherwise",
@@ -73,6 +91,8 @@ func NewDropletCollector(logger log.Logger, errors *prometheus.CounterVec, clien
 // Describe sends the super-set of all possible descriptors of metrics
 // collected by this Collector.
 func (c *DropletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.Created
 	ch <- c.Up
 	ch <- c.CPUs
 	ch <- c.Memory
@@ -86,50 +106,67 @@ func (c *DropletCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	// create a list to hold our droplets
-	droplets := []godo.Droplet{}
-
-	// create options. initially, these will be blank
-	opt := &godo.ListOptions{}
+	droplets, err := listDroplets(ctx, c.client)
+	if err != nil {
+		c.errors.WithLabelValues("droplet").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list droplets",
+			"err", err,
+		)
+		return
+	}
 
-	for {
-		dropletsPage, resp, err := c.client.Droplets.List(ctx, opt)
-		if err != nil {
-			c.errors.WithLabelValues("droplet").Add(1)
-			level.Warn(c.logger).Log(
-				"msg", "can't list droplets",
-				"err", err,
-			)
-			return
+	for _, droplet := range droplets {
+		labels := []string{
+			fmt.Sprintf("%d", droplet.ID),
+			droplet.Name,
+			droplet.Region.Slug,
 		}
 
-		// append the current page's droplets to our list
-		for _, d := range dropletsPage {
-			droplets = append(droplets, d)
+		var imageDistribution, imageSlug string
+		if droplet.Image != nil {
+			imageDistribution = droplet.Image.Distribution
+			imageSlug = droplet.Image.Slug
 		}
+		// The addresses are only missing if the droplet has no networks yet,
+		// in which case empty labels are what we want anyway.
+		publicIPv4, _ := droplet.PublicIPv4()
+		privateIPv4, _ := droplet.PrivateIPv4()
+		publicIPv6, _ := droplet.PublicIPv6()
 
-		// if we are at the last page, break out the for loop
-		if resp.Links == nil || resp.Links.IsLastPage() {
-			break
-		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			fmt.Sprintf("%d", droplet.ID),
+			droplet.Name,
+			droplet.Region.Slug,
+			droplet.SizeSlug,
+			imageDistribution,
+			imageSlug,
+			droplet.VPCUUID,
+			publicIPv4,
+			privateIPv4,
+			publicIPv6,
+			joinList(droplet.Tags),
+			joinList(droplet.Features),
+		)
 
-		page, err := resp.Links.CurrentPage()
+		created, err := time.Parse(time.RFC3339, droplet.Created)
 		if err != nil {
 			c.errors.WithLabelValues("droplet").Add(1)
 			level.Warn(c.logger).Log(
-				"msg", "can't read current page",
+				"msg", "can't parse droplet creation time",
+				"droplet", droplet.ID,
 				"err", err,
 			)
-		}
-
-		opt.Page = page + 1
-	}
-
-	for _, droplet := range droplets {
-		labels := []string{
-			fmt.Sprintf("%d", droplet.ID),
-			droplet.Name,
-			droplet.Region.Slug,
+		} else {
+			ch <- prometheus.MustNewConstMetric(
+				c.Created,
+				prometheus.GaugeValue,
+				float64(created.Unix()),
+				labels...,
+			)
 		}
 
 		var active float64
@@ -174,3 +211,24 @@ func (c *DropletCollector) Collect(ch chan<- prometheus.Metric) {
 		)
 	}
 }
+
+// listDroplets pages through all droplets of the account.
+func listDroplets(ctx context.Context, client *godo.Client) ([]godo.Droplet, error) {
+	droplets := []godo.Droplet{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := client.Droplets.List(ctx, opt)
+		droplets = append(droplets, page...)
+		return resp, err
+	})
+	return droplets, err
+}
+
+// joinList joins the list with commas and surrounds it with commas too,
+// so that a single entry can be matched with a regex like ".*,prod,.*".
+// An empty list is joined to an empty string.
+func joinList(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return "," + strings.Join(list, ",") + ","
+}
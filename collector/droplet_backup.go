@@ -0,0 +1,208 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// backupConcurrency is how many droplets' backups are listed at the same time.
+const backupConcurrency = 5
+
+// DropletBackupCollector collects whether droplets have backups enabled,
+// when their newest backup and snapshot were taken and their next backup window.
+type DropletBackupCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	BackupsEnabled    *prometheus.Desc
+	LastBackup        *prometheus.Desc
+	LastSnapshot      *prometheus.Desc
+	BackupWindowStart *prometheus.Desc
+	BackupWindowEnd   *prometheus.Desc
+}
+
+// NewDropletBackupCollector returns a new DropletBackupCollector.
+func NewDropletBackupCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *DropletBackupCollector {
+	errors.WithLabelValues("droplet_backup").Add(0)
+
+	labels := []string{"id", "name", "region"}
+	return &DropletBackupCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		BackupsEnabled: prometheus.NewDesc(
+			"digitalocean_droplet_backups_enabled",
+			"If 1 the droplet has backups enabled, 0 otherwise",
+			labels, nil,
+		),
+		LastBackup: prometheus.NewDesc(
+			"digitalocean_droplet_backup_last_timestamp_seconds",
+			"Unix timestamp of the droplet's newest backup",
+			labels, nil,
+		),
+		LastSnapshot: prometheus.NewDesc(
+			"digitalocean_droplet_snapshot_last_timestamp_seconds",
+			"Unix timestamp of the droplet's newest snapshot",
+			labels, nil,
+		),
+		BackupWindowStart: prometheus.NewDesc(
+			"digitalocean_droplet_backup_window_start_timestamp_seconds",
+			"Unix timestamp of the start of the droplet's next backup window",
+			labels, nil,
+		),
+		BackupWindowEnd: prometheus.NewDesc(
+			"digitalocean_droplet_backup_window_end_timestamp_seconds",
+			"Unix timestamp of the end of the droplet's next backup window",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *DropletBackupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BackupsEnabled
+	ch <- c.LastBackup
+	ch <- c.LastSnapshot
+	ch <- c.BackupWindowStart
+	ch <- c.BackupWindowEnd
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *DropletBackupCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	droplets, err := listDroplets(ctx, c.client)
+	if err != nil {
+		c.errors.WithLabelValues("droplet_backup").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list droplets",
+			"err", err,
+		)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, backupConcurrency)
+	for _, droplet := range droplets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(droplet godo.Droplet) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			for _, err := range c.collectDroplet(ctx, ch, droplet) {
+				c.errors.WithLabelValues("droplet_backup").Add(1)
+				level.Warn(c.logger).Log(
+					"msg", "can't get droplet backups",
+					"droplet", droplet.ID,
+					"err", err,
+				)
+			}
+		}(droplet)
+	}
+	wg.Wait()
+}
+
+// collectDroplet collects the backups and snapshots of a single droplet.
+// All droplets share the timeout of the collection, so a scrape doesn't take longer the more droplets there are.
+// Failing to list the backups doesn't keep the snapshots from being collected, the errors of both are returned.
+func (c *DropletBackupCollector) collectDroplet(ctx context.Context, ch chan<- prometheus.Metric, droplet godo.Droplet) []error {
+	var region string
+	if droplet.Region != nil {
+		region = droplet.Region.Slug
+	}
+	labels := []string{
+		fmt.Sprintf("%d", droplet.ID),
+		droplet.Name,
+		region,
+	}
+
+	enabled := 0.0
+	for _, feature := range droplet.Features {
+		if feature == "backups" {
+			enabled = 1
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.BackupsEnabled,
+		prometheus.GaugeValue,
+		enabled,
+		labels...,
+	)
+
+	if w := droplet.NextBackupWindow; w != nil && w.Start != nil && w.End != nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.BackupWindowStart,
+			prometheus.GaugeValue,
+			float64(w.Start.Unix()),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.BackupWindowEnd,
+			prometheus.GaugeValue,
+			float64(w.End.Unix()),
+			labels...,
+		)
+	}
+
+	var errs []error
+	// The droplet knows the ids of its backups and snapshots,
+	// droplets without any don't need to be asked for them.
+	for _, images := range []struct {
+		name string
+		ids  []int
+		desc *prometheus.Desc
+		list func(context.Context, int, *godo.ListOptions) ([]godo.Image, *godo.Response, error)
+	}{
+		{"backups", droplet.BackupIDs, c.LastBackup, c.client.Droplets.Backups},
+		{"snapshots", droplet.SnapshotIDs, c.LastSnapshot, c.client.Droplets.Snapshots},
+	} {
+		if len(images.ids) == 0 {
+			continue
+		}
+
+		var newest time.Time
+		err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+			page, resp, err := images.list(ctx, droplet.ID, opt)
+			for _, image := range page {
+				created, err := time.Parse(time.RFC3339, image.Created)
+				if err != nil {
+					return resp, fmt.Errorf("can't parse creation time of %d: %w", image.ID, err)
+				}
+				if created.After(newest) {
+					newest = created
+				}
+			}
+			return resp, err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("can't list %s: %w", images.name, err))
+			continue
+		}
+		if newest.IsZero() {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			images.desc,
+			prometheus.GaugeValue,
+			float64(newest.Unix()),
+			labels...,
+		)
+	}
+
+	return errs
+}
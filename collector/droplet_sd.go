@@ -0,0 +1,252 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const sdLabelPrefix = "__meta_digitalocean_"
+
+// TargetGroup is a group of scrape targets sharing the same labels,
+// as understood by Prometheus' http_sd_configs and file_sd_configs.
+type TargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// DropletFilter restricts which droplets are turned into targets.
+// Empty fields match every droplet.
+type DropletFilter struct {
+	Tag    string
+	Region string
+}
+
+func (f DropletFilter) matches(droplet godo.Droplet) bool {
+	if f.Region != "" && (droplet.Region == nil || droplet.Region.Slug != f.Region) {
+		return false
+	}
+	if f.Tag == "" {
+		return true
+	}
+	for _, tag := range droplet.Tags {
+		if tag == f.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// DropletDiscovery turns the droplets of all added accounts into Prometheus scrape targets.
+type DropletDiscovery struct {
+	logger   log.Logger
+	timeout  time.Duration
+	port     int
+	accounts []discoveryAccount
+}
+
+type discoveryAccount struct {
+	name   string
+	errors *prometheus.CounterVec
+	client *godo.Client
+}
+
+// NewDropletDiscovery returns a new DropletDiscovery.
+// Targets are addressed by the droplet's public IPv4 and the given port.
+func NewDropletDiscovery(logger log.Logger, timeout time.Duration, port int) *DropletDiscovery {
+	return &DropletDiscovery{
+		logger:  logger,
+		timeout: timeout,
+		port:    port,
+	}
+}
+
+// AddAccount adds the droplets of another account to the discovered targets.
+func (d *DropletDiscovery) AddAccount(name string, errors *prometheus.CounterVec, client *godo.Client) {
+	errors.WithLabelValues("droplet_sd").Add(0)
+
+	d.accounts = append(d.accounts, discoveryAccount{
+		name:   name,
+		errors: errors,
+		client: client,
+	})
+}
+
+// TargetGroups lists all droplets matching the filter as target groups.
+func (d *DropletDiscovery) TargetGroups(ctx context.Context, filter DropletFilter) ([]TargetGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	groups := []TargetGroup{}
+	for _, account := range d.accounts {
+		droplets, err := listDroplets(ctx, account.client)
+		if err != nil {
+			account.errors.WithLabelValues("droplet_sd").Add(1)
+			return nil, fmt.Errorf("account %s: %w", account.name, err)
+		}
+
+		for _, droplet := range droplets {
+			if !filter.matches(droplet) {
+				continue
+			}
+			group, ok := d.targetGroup(account.name, droplet)
+			if !ok {
+				level.Debug(d.logger).Log(
+					"msg", "skipping droplet without an ipv4 address",
+					"account", account.name,
+					"droplet", droplet.ID,
+				)
+				continue
+			}
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
+// targetGroup returns the droplet's target group.
+// Droplets are addressed by their public IPv4, VPC-only droplets by their private IPv4.
+// Droplets without any IPv4 can't be scraped and have no target group.
+func (d *DropletDiscovery) targetGroup(account string, droplet godo.Droplet) (TargetGroup, bool) {
+	publicIPv4, _ := droplet.PublicIPv4()
+	privateIPv4, _ := droplet.PrivateIPv4()
+	publicIPv6, _ := droplet.PublicIPv6()
+
+	address := publicIPv4
+	if address == "" {
+		address = privateIPv4
+	}
+	if address == "" {
+		return TargetGroup{}, false
+	}
+
+	var region, image string
+	if droplet.Region != nil {
+		region = droplet.Region.Slug
+	}
+	if droplet.Image != nil {
+		image = droplet.Image.Slug
+	}
+
+	labels := map[string]string{
+		sdLabelPrefix + "account":      account,
+		sdLabelPrefix + "droplet_id":   strconv.Itoa(droplet.ID),
+		sdLabelPrefix + "droplet_name": droplet.Name,
+		sdLabelPrefix + "status":       droplet.Status,
+		sdLabelPrefix + "region":       region,
+		sdLabelPrefix + "size":         droplet.SizeSlug,
+		sdLabelPrefix + "image":        image,
+		sdLabelPrefix + "vpc":          droplet.VPCUUID,
+		sdLabelPrefix + "public_ipv4":  publicIPv4,
+		sdLabelPrefix + "private_ipv4": privateIPv4,
+		sdLabelPrefix + "public_ipv6":  publicIPv6,
+	}
+	if len(droplet.Tags) > 0 {
+		labels[sdLabelPrefix+"tags"] = joinList(droplet.Tags)
+	}
+	if len(droplet.Features) > 0 {
+		labels[sdLabelPrefix+"features"] = joinList(droplet.Features)
+	}
+
+	return TargetGroup{
+		Targets: []string{net.JoinHostPort(address, strconv.Itoa(d.port))},
+		Labels:  labels,
+	}, true
+}
+
+// ServeHTTP serves the droplets as http_sd_config JSON.
+// The tag and region query parameters filter the returned droplets.
+func (d *DropletDiscovery) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter := DropletFilter{
+		Tag:    r.URL.Query().Get("tag"),
+		Region: r.URL.Query().Get("region"),
+	}
+
+	groups, err := d.TargetGroups(r.Context(), filter)
+	if err != nil {
+		level.Warn(d.logger).Log(
+			"msg", "can't discover droplets",
+			"err", err,
+		)
+		http.Error(w, "can't discover droplets", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(groups); err != nil {
+		level.Warn(d.logger).Log(
+			"msg", "can't write droplet targets",
+			"err", err,
+		)
+	}
+}
+
+// WriteFile writes the droplets matching the filter as file_sd_config JSON to path.
+// The file is replaced atomically, so Prometheus never reads a partial file.
+func (d *DropletDiscovery) WriteFile(ctx context.Context, path string, filter DropletFilter) error {
+	groups, err := d.TargetGroups(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// TempFile creates files only readable by us, Prometheus might run as another user.
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// RunFile writes the file_sd file every interval until the context is canceled.
+func (d *DropletDiscovery) RunFile(ctx context.Context, path string, filter DropletFilter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.WriteFile(ctx, path, filter); err != nil {
+			level.Warn(d.logger).Log(
+				"msg", "can't write droplet file_sd file",
+				"path", path,
+				"err", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/digitalocean/godo/metrics"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// utilizationWindow is how far back the Monitoring API is queried.
+	// The agent reports about every minute, the latest sample is within this window.
+	utilizationWindow = 5 * time.Minute
+	// utilizationConcurrency is how many droplets are queried at the same time.
+	utilizationConcurrency = 5
+)
+
+// DropletUtilizationCollector collects CPU, memory, filesystem and load metrics
+// of all droplets running the DigitalOcean metrics agent from the Monitoring API.
+type DropletUtilizationCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	CPU             *prometheus.Desc
+	MemoryTotal     *prometheus.Desc
+	MemoryAvailable *prometheus.Desc
+	MemoryFree      *prometheus.Desc
+	MemoryCached    *prometheus.Desc
+	FilesystemSize  *prometheus.Desc
+	FilesystemFree  *prometheus.Desc
+	Load1           *prometheus.Desc
+	Load5           *prometheus.Desc
+	Load15          *prometheus.Desc
+}
+
+// NewDropletUtilizationCollector returns a new DropletUtilizationCollector.
+func NewDropletUtilizationCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *DropletUtilizationCollector {
+	errors.WithLabelValues("droplet_utilization").Add(0)
+
+	labels := []string{"id", "name", "region"}
+	filesystemLabels := []string{"id", "name", "region", "device", "fstype", "mountpoint"}
+	return &DropletUtilizationCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		CPU: prometheus.NewDesc(
+			"digitalocean_droplet_cpu_seconds_total",
+			"Seconds the droplet's CPUs spent in each mode",
+			[]string{"id", "name", "region", "mode"}, nil,
+		),
+		MemoryTotal: prometheus.NewDesc(
+			"digitalocean_droplet_memory_total_bytes",
+			"Droplet's total memory in bytes as reported by the metrics agent",
+			labels, nil,
+		),
+		MemoryAvailable: prometheus.NewDesc(
+			"digitalocean_droplet_memory_available_bytes",
+			"Droplet's memory available for starting new applications in bytes",
+			labels, nil,
+		),
+		MemoryFree: prometheus.NewDesc(
+			"digitalocean_droplet_memory_free_bytes",
+			"Droplet's unused memory in bytes",
+			labels, nil,
+		),
+		MemoryCached: prometheus.NewDesc(
+			"digitalocean_droplet_memory_cached_bytes",
+			"Droplet's memory used by the page cache in bytes",
+			labels, nil,
+		),
+		FilesystemSize: prometheus.NewDesc(
+			"digitalocean_droplet_filesystem_size_bytes",
+			"Size of the droplet's filesystem in bytes",
+			filesystemLabels, nil,
+		),
+		FilesystemFree: prometheus.NewDesc(
+			"digitalocean_droplet_filesystem_free_bytes",
+			"Free space of the droplet's filesystem in bytes",
+			filesystemLabels, nil,
+		),
+		Load1: prometheus.NewDesc(
+			"digitalocean_droplet_load1",
+			"Droplet's 1m load average",
+			labels, nil,
+		),
+		Load5: prometheus.NewDesc(
+			"digitalocean_droplet_load5",
+			"Droplet's 5m load average",
+			labels, nil,
+		),
+		Load15: prometheus.NewDesc(
+			"digitalocean_droplet_load15",
+			"Droplet's 15m load average",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *DropletUtilizationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.CPU
+	ch <- c.MemoryTotal
+	ch <- c.MemoryAvailable
+	ch <- c.MemoryFree
+	ch <- c.MemoryCached
+	ch <- c.FilesystemSize
+	ch <- c.FilesystemFree
+	ch <- c.Load1
+	ch <- c.Load5
+	ch <- c.Load15
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *DropletUtilizationCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	droplets, err := listDroplets(ctx, c.client)
+	if err != nil {
+		c.errors.WithLabelValues("droplet_utilization").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list droplets",
+			"err", err,
+		)
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, utilizationConcurrency)
+	for _, droplet := range droplets {
+		// Droplets that are off don't report any metrics.
+		if droplet.Status != "active" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(droplet godo.Droplet) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.collectDroplet(ctx, ch, droplet); err != nil {
+				c.errors.WithLabelValues("droplet_utilization").Add(1)
+				level.Warn(c.logger).Log(
+					"msg", "can't get droplet metrics",
+					"droplet", droplet.ID,
+					"err", err,
+				)
+			}
+		}(droplet)
+	}
+	wg.Wait()
+}
+
+// collectDroplet collects the latest metrics of a single droplet.
+// All droplets share the timeout of the collection like the droplet_backup collector,
+// so the ten requests per droplet don't make a scrape take longer the more droplets there are.
+func (c *DropletUtilizationCollector) collectDroplet(ctx context.Context, ch chan<- prometheus.Metric, droplet godo.Droplet) error {
+	var region string
+	if droplet.Region != nil {
+		region = droplet.Region.Slug
+	}
+	labels := []string{
+		fmt.Sprintf("%d", droplet.ID),
+		droplet.Name,
+		region,
+	}
+
+	now := time.Now()
+	req := &godo.DropletMetricsRequest{
+		HostID: fmt.Sprintf("%d", droplet.ID),
+		Start:  now.Add(-utilizationWindow),
+		End:    now,
+	}
+
+	cpu, _, err := c.client.Monitoring.GetDropletCPU(ctx, req)
+	if err != nil {
+		return fmt.Errorf("can't get cpu: %w", err)
+	}
+	for _, stream := range cpu.Data.Result {
+		value, ok := latest(stream)
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.CPU,
+			prometheus.CounterValue,
+			value,
+			append(labels, string(stream.Metric["mode"]))...,
+		)
+	}
+
+	for _, m := range []struct {
+		name string
+		desc *prometheus.Desc
+		get  func(context.Context, *godo.DropletMetricsRequest) (*godo.MetricsResponse, *godo.Response, error)
+	}{
+		{"memory_total", c.MemoryTotal, c.client.Monitoring.GetDropletTotalMemory},
+		{"memory_available", c.MemoryAvailable, c.client.Monitoring.GetDropletAvailableMemory},
+		{"memory_free", c.MemoryFree, c.client.Monitoring.GetDropletFreeMemory},
+		{"memory_cached", c.MemoryCached, c.client.Monitoring.GetDropletCachedMemory},
+		{"load_1", c.Load1, c.client.Monitoring.GetDropletLoad1},
+		{"load_5", c.Load5, c.client.Monitoring.GetDropletLoad5},
+		{"load_15", c.Load15, c.client.Monitoring.GetDropletLoad15},
+	} {
+		resp, _, err := m.get(ctx, req)
+		if err != nil {
+			return fmt.Errorf("can't get %s: %w", m.name, err)
+		}
+		// Droplets without the metrics agent have no results.
+		if len(resp.Data.Result) == 0 {
+			continue
+		}
+		value, ok := latest(resp.Data.Result[0])
+		if !ok {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			m.desc,
+			prometheus.GaugeValue,
+			value,
+			labels...,
+		)
+	}
+
+	for _, m := range []struct {
+		name string
+		desc *prometheus.Desc
+		get  func(context.Context, *godo.DropletMetricsRequest) (*godo.MetricsResponse, *godo.Response, error)
+	}{
+		{"filesystem_size", c.FilesystemSize, c.client.Monitoring.GetDropletFilesystemSize},
+		{"filesystem_free", c.FilesystemFree, c.client.Monitoring.GetDropletFilesystemFree},
+	} {
+		resp, _, err := m.get(ctx, req)
+		if err != nil {
+			return fmt.Errorf("can't get %s: %w", m.name, err)
+		}
+		for _, stream := range resp.Data.Result {
+			value, ok := latest(stream)
+			if !ok {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				m.desc,
+				prometheus.GaugeValue,
+				value,
+				append(labels,
+					string(stream.Metric["device"]),
+					string(stream.Metric["fstype"]),
+					string(stream.Metric["mountpoint"]),
+				)...,
+			)
+		}
+	}
+
+	return nil
+}
+
+// latest returns the most recent value of the stream.
+func latest(stream metrics.SampleStream) (float64, bool) {
+	if len(stream.Values) == 0 {
+		return 0, false
+	}
+	return float64(stream.Values[len(stream.Values)-1].Value), true
+}
@@ -51,6 +51,7 @@ func NewExporterCollector(logger log.Logger, version string, revision string, bu
 // collected by this Collector.
 func (c *ExporterCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.StartTime
+	ch <- c.BuildInfo
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
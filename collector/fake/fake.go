@@ -0,0 +1,275 @@
+// Package fake provides a stand-in for the DigitalOcean API, the DigitalOcean status page
+// and the Spaces S3 API to test the collectors without a real token.
+//
+// Responses are read from fixture files in a directory mirroring the request paths:
+// a request to /v2/droplets is answered with <dir>/v2/droplets.json,
+// a request to /api/v2/summary.json with <dir>/api/v2/summary.json
+// and the S3 ListBuckets request to / of <region>.digitaloceanspaces.com with <dir>/<region>/ListBuckets.xml.
+//
+// Fixtures of the /v2 API with a single list in them are paginated
+// like the real API does, honoring the page and per_page query parameters.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const defaultPerPage = 20
+
+// Server is a fake DigitalOcean API serving fixtures from a directory.
+type Server struct {
+	*httptest.Server
+
+	dir string
+
+	mtx     sync.Mutex
+	perPage int
+	errors  map[string]apiError
+	rate    *godo.Rate
+}
+
+type apiError struct {
+	status  int
+	message string
+}
+
+// NewServer starts a new Server serving the fixtures in dir.
+// It has to be closed by calling Close.
+func NewServer(dir string) *Server {
+	s := &Server{
+		dir:    dir,
+		errors: map[string]apiError{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// GodoClient returns a godo.Client sending all its requests to the Server.
+func (s *Server) GodoClient() *godo.Client {
+	client, err := godo.New(s.Client(), godo.SetBaseURL(s.URL+"/"))
+	if err != nil {
+		// The URL of a httptest.Server is always valid.
+		panic(err)
+	}
+	return client
+}
+
+// SpacesTransport returns a http.RoundTripper sending all requests to the Server,
+// keeping their Host so the Spaces endpoints of the regions can be told apart.
+func (s *Server) SpacesTransport() http.RoundTripper {
+	addr := s.Listener.Addr().String()
+	return &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// SetPerPage limits the number of items on every page,
+// regardless of the per_page query parameter.
+// This makes sure small fixtures span multiple pages.
+func (s *Server) SetPerPage(n int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.perPage = n
+}
+
+// Fail makes all requests to path fail with the status code and message.
+func (s *Server) Fail(path string, status int, message string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.errors[path] = apiError{status: status, message: message}
+}
+
+// SetRateLimit sets the rate limit headers of all responses.
+func (s *Server) SetRateLimit(limit, remaining int, reset time.Time) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.rate = &godo.Rate{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     godo.Timestamp{Time: reset},
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	apiErr, failing := s.errors[r.URL.Path]
+	perPage := s.perPage
+	rate := s.rate
+	s.mtx.Unlock()
+
+	if rate != nil {
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(rate.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(rate.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(rate.Reset.Unix(), 10))
+	}
+
+	if failing {
+		writeError(w, apiErr.status, apiErr.message)
+		return
+	}
+
+	if r.URL.Path == "/" {
+		region := strings.SplitN(r.Host, ".", 2)[0]
+		s.serveFile(w, filepath.Join(s.dir, region, "ListBuckets.xml"), "application/xml")
+		return
+	}
+
+	file := filepath.Join(s.dir, filepath.FromSlash(r.URL.Path))
+	if !strings.HasSuffix(file, ".json") {
+		file += ".json"
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/v2/") {
+		s.serveFile(w, file, "application/json")
+		return
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		writeError(w, http.StatusNotFound, "The resource you were accessing could not be found.")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	content, err = paginate(r, content, perPage)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("can't paginate %s: %v", file, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(content)
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, file string, contentType string) {
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		http.NotFound(w, nil)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(content)
+}
+
+// writeError writes an error in the format of the DigitalOcean API.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"id":      strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_")),
+		"message": message,
+	})
+}
+
+// paginate returns the requested page of the only list in the response.
+// Responses without or with multiple lists are returned unchanged.
+func paginate(r *http.Request, content []byte, maxPerPage int) ([]byte, error) {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(content, &response); err != nil {
+		// Not an object, nothing to paginate.
+		return content, nil
+	}
+
+	var listKey string
+	var list []json.RawMessage
+	for key, value := range response {
+		if key == "links" || key == "meta" {
+			continue
+		}
+		var l []json.RawMessage
+		if err := json.Unmarshal(value, &l); err != nil {
+			continue
+		}
+		if listKey != "" {
+			return content, nil
+		}
+		listKey, list = key, l
+	}
+	if listKey == "" {
+		return content, nil
+	}
+
+	page := queryInt(r, "page", 1)
+	perPage := queryInt(r, "per_page", defaultPerPage)
+	if maxPerPage > 0 && perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+	lastPage := (len(list) + perPage - 1) / perPage
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	start := (page - 1) * perPage
+	if start > len(list) {
+		start = len(list)
+	}
+	end := start + perPage
+	if end > len(list) {
+		end = len(list)
+	}
+
+	pageURL := func(p int) string {
+		u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	pages := godo.Pages{}
+	if page > 1 {
+		pages.First = pageURL(1)
+		pages.Prev = pageURL(page - 1)
+	}
+	if page < lastPage {
+		pages.Next = pageURL(page + 1)
+		pages.Last = pageURL(lastPage)
+	}
+
+	var err error
+	if response[listKey], err = json.Marshal(list[start:end]); err != nil {
+		return nil, err
+	}
+	if response["links"], err = json.Marshal(godo.Links{Pages: &pages}); err != nil {
+		return nil, err
+	}
+	if response["meta"], err = json.Marshal(godo.Meta{Total: len(list)}); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(response)
+}
+
+func queryInt(r *http.Request, key string, def int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil || v < 1 {
+		return def
+	}
+	return v
+}
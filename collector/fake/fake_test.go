@@ -0,0 +1,104 @@
+package fake
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+func newServer(t *testing.T) *Server {
+	dir, err := ioutil.TempDir("", "fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := os.MkdirAll(filepath.Join(dir, "v2", "account"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	keys := `{"ssh_keys": [{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "v2", "account", "keys.json"), []byte(keys), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(dir)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestPagination(t *testing.T) {
+	s := newServer(t)
+	s.SetPerPage(2)
+	client := s.GodoClient()
+
+	var ids []int
+	opt := &godo.ListOptions{PerPage: 200}
+	for {
+		keys, resp, err := client.Keys.List(context.Background(), opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range keys {
+			ids = append(ids, k.ID)
+		}
+		if resp.Meta == nil || resp.Meta.Total != 5 {
+			t.Errorf("expected meta total of 5, got %+v", resp.Meta)
+		}
+		if resp.Links.IsLastPage() {
+			break
+		}
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		opt.Page = page + 1
+	}
+
+	if len(ids) != 5 || ids[0] != 1 || ids[4] != 5 {
+		t.Errorf("expected keys 1 to 5, got %v", ids)
+	}
+}
+
+func TestFail(t *testing.T) {
+	s := newServer(t)
+	s.Fail("/v2/account/keys", http.StatusForbidden, "forbidden")
+
+	_, _, err := s.GodoClient().Keys.List(context.Background(), nil)
+	errResp, ok := err.(*godo.ErrorResponse)
+	if !ok {
+		t.Fatalf("expected a godo.ErrorResponse, got %v", err)
+	}
+	if errResp.Response.StatusCode != http.StatusForbidden || errResp.Message != "forbidden" {
+		t.Errorf("unexpected error: %v", errResp)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := newServer(t)
+	reset := time.Unix(1600000000, 0)
+	s.SetRateLimit(5000, 42, reset)
+
+	_, resp, err := s.GodoClient().Keys.List(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Rate.Limit != 5000 || resp.Rate.Remaining != 42 || !resp.Rate.Reset.Time.Equal(reset) {
+		t.Errorf("unexpected rate: %+v", resp.Rate)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	s := newServer(t)
+
+	_, _, err := s.GodoClient().Droplets.List(context.Background(), nil)
+	errResp, ok := err.(*godo.ErrorResponse)
+	if !ok || errResp.Response.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
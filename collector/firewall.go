@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// firewallStates are all states a firewall can be in.
+var firewallStates = []string{"waiting", "succeeded", "failed"}
+
+// FirewallCollector collects metrics about Cloud Firewalls of that account.
+type FirewallCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	State          *prometheus.Desc
+	InboundRules   *prometheus.Desc
+	OutboundRules  *prometheus.Desc
+	Droplets       *prometheus.Desc
+	Tags           *prometheus.Desc
+	PendingChanges *prometheus.Desc
+}
+
+// NewFirewallCollector returns a new FirewallCollector.
+func NewFirewallCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *FirewallCollector {
+	errors.WithLabelValues("firewall").Add(0)
+
+	labels := []string{"id", "name"}
+	return &FirewallCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		State: prometheus.NewDesc(
+			"digitalocean_firewall_state",
+			"The state of the firewall's rules being applied, 1 for the current state, 0 for all others",
+			[]string{"id", "name", "state"}, nil,
+		),
+		InboundRules: prometheus.NewDesc(
+			"digitalocean_firewall_inbound_rules",
+			"The number of inbound rules of the firewall",
+			labels, nil,
+		),
+		OutboundRules: prometheus.NewDesc(
+			"digitalocean_firewall_outbound_rules",
+			"The number of outbound rules of the firewall",
+			labels, nil,
+		),
+		Droplets: prometheus.NewDesc(
+			"digitalocean_firewall_droplets",
+			"The number of droplets the firewall is applied to",
+			labels, nil,
+		),
+		Tags: prometheus.NewDesc(
+			"digitalocean_firewall_tags",
+			"The number of tags the firewall is applied to",
+			labels, nil,
+		),
+		PendingChanges: prometheus.NewDesc(
+			"digitalocean_firewall_pending_changes",
+			"The number of changes of the firewall waiting to be applied to droplets",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *FirewallCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.State
+	ch <- c.InboundRules
+	ch <- c.OutboundRules
+	ch <- c.Droplets
+	ch <- c.Tags
+	ch <- c.PendingChanges
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *FirewallCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	firewalls := []godo.Firewall{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Firewalls.List(ctx, opt)
+		firewalls = append(firewalls, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("firewall").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list firewalls",
+			"err", err,
+		)
+		return
+	}
+
+	for _, fw := range firewalls {
+		stateMetrics(ch, c.State, firewallStates, fw.Status, fw.ID, fw.Name)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.InboundRules,
+			prometheus.GaugeValue,
+			float64(len(fw.InboundRules)),
+			fw.ID, fw.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.OutboundRules,
+			prometheus.GaugeValue,
+			float64(len(fw.OutboundRules)),
+			fw.ID, fw.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Droplets,
+			prometheus.GaugeValue,
+			float64(len(fw.DropletIDs)),
+			fw.ID, fw.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Tags,
+			prometheus.GaugeValue,
+			float64(len(fw.Tags)),
+			fw.ID, fw.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.PendingChanges,
+			prometheus.GaugeValue,
+			float64(len(fw.PendingChanges)),
+			fw.ID, fw.Name,
+		)
+	}
+}
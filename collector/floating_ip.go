@@ -50,7 +50,12 @@ func (c *FloatingIPCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *FloatingIPCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	floatingIPs, _, err := c.client.FloatingIPs.List(ctx, nil)
+	floatingIPs := []godo.FloatingIP{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.FloatingIPs.List(ctx, opt)
+		floatingIPs = append(floatingIPs, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("floating_ip").Add(1)
 		level.Warn(c.logger).Log(
@@ -50,7 +50,12 @@ func (c *ImageCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *ImageCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	images, _, err := c.client.Images.ListUser(ctx, nil)
+	images := []godo.Image{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Images.ListUser(ctx, opt)
+		images = append(images, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("image").Add(1)
 		level.Warn(c.logger).Log(
@@ -26,9 +26,10 @@ type DOIncidentAPIResponse struct {
 
 // IncidentCollector collects number of active incidents associated with digital ocean services
 type IncidentCollector struct {
-	logger  log.Logger
-	errors  *prometheus.CounterVec
-	timeout time.Duration
+	logger    log.Logger
+	errors    *prometheus.CounterVec
+	timeout   time.Duration
+	statusURL string
 
 	Incidents      *prometheus.Desc
 	IncidentsTotal *prometheus.Desc
@@ -40,9 +41,10 @@ func NewIncidentCollector(logger log.Logger, errors *prometheus.CounterVec, time
 
 	labels := []string{"region"}
 	return &IncidentCollector{
-		logger:  logger,
-		errors:  errors,
-		timeout: timeout,
+		logger:    logger,
+		errors:    errors,
+		timeout:   timeout,
+		statusURL: doStatusAPIURL,
 
 		Incidents: prometheus.NewDesc(
 			"digitalocean_incidents",
@@ -61,11 +63,16 @@ func NewIncidentCollector(logger log.Logger, errors *prometheus.CounterVec, time
 // collected by this Collector.
 func (c *IncidentCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Incidents
+	ch <- c.IncidentsTotal
 }
 
 // GetIncidents fetches active incidents associated with digital ocean services
 func GetIncidents(client *http.Client) (DOIncidentAPIResponse, error) {
-	r, err := client.Get(doStatusAPIURL)
+	return getIncidents(client, doStatusAPIURL)
+}
+
+func getIncidents(client *http.Client, url string) (DOIncidentAPIResponse, error) {
+	r, err := client.Get(url)
 	if err != nil {
 		return DOIncidentAPIResponse{}, err
 	}
@@ -98,7 +105,7 @@ func (c *IncidentCollector) Collect(ch chan<- prometheus.Metric) {
 	// Datastore to count all incidents per region
 	regionalIncidents := make(map[string]int)
 	client := http.Client{Timeout: c.timeout}
-	doStatus, err := GetIncidents(&client)
+	doStatus, err := getIncidents(&client, c.statusURL)
 	if err != nil {
 		c.errors.WithLabelValues("incidents").Add(1)
 		level.Warn(c.logger).Log(
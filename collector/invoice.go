@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// invoiceSummaries is the number of most recent invoices whose summary is collected.
+const invoiceSummaries = 12
+
+// InvoiceCollector collects the amounts of all invoices
+// and the per-product summary of the most recent ones.
+type InvoiceCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	// Invoices never change once issued, so their summaries are only requested once.
+	mtx       sync.Mutex
+	summaries map[string]*godo.InvoiceSummary
+
+	Amount        *prometheus.Desc
+	ProductAmount *prometheus.Desc
+	SummaryAmount *prometheus.Desc
+}
+
+// NewInvoiceCollector returns a new InvoiceCollector.
+func NewInvoiceCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *InvoiceCollector {
+	errors.WithLabelValues("invoice").Add(0)
+
+	return &InvoiceCollector{
+		logger:    logger,
+		errors:    errors,
+		client:    client,
+		timeout:   timeout,
+		summaries: map[string]*godo.InvoiceSummary{},
+
+		Amount: prometheus.NewDesc(
+			"digitalocean_invoice_amount",
+			"The invoice's total amount in dollars",
+			[]string{"uuid", "period"}, nil,
+		),
+		ProductAmount: prometheus.NewDesc(
+			"digitalocean_invoice_product_amount",
+			"The invoice's usage charges of a product in dollars",
+			[]string{"uuid", "period", "product"}, nil,
+		),
+		SummaryAmount: prometheus.NewDesc(
+			"digitalocean_invoice_summary_amount",
+			"The invoice's amount of product charges, overages, taxes and credits and adjustments in dollars",
+			[]string{"uuid", "period", "category"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *InvoiceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Amount
+	ch <- c.ProductAmount
+	ch <- c.SummaryAmount
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *InvoiceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	invoices := []godo.InvoiceListItem{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Invoices.List(ctx, opt)
+		if page != nil {
+			invoices = append(invoices, page.Invoices...)
+		}
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("invoice").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list invoices",
+			"err", err,
+		)
+		return
+	}
+
+	for _, invoice := range invoices {
+		amount, err := strconv.ParseFloat(invoice.Amount, 64)
+		if err != nil {
+			c.errors.WithLabelValues("invoice").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't parse invoice amount",
+				"invoice", invoice.InvoiceUUID,
+				"err", err,
+			)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Amount,
+			prometheus.GaugeValue,
+			amount,
+			invoice.InvoiceUUID, invoice.InvoicePeriod,
+		)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// The API lists the most recent invoices first.
+	recent := invoices
+	if len(recent) > invoiceSummaries {
+		recent = recent[:invoiceSummaries]
+	}
+	summaries := make(map[string]*godo.InvoiceSummary, len(recent))
+	for _, invoice := range recent {
+		summary, ok := c.summaries[invoice.InvoiceUUID]
+		if !ok {
+			summary, _, err = c.client.Invoices.GetSummary(ctx, invoice.InvoiceUUID)
+			if err != nil {
+				c.errors.WithLabelValues("invoice").Add(1)
+				level.Warn(c.logger).Log(
+					"msg", "can't get invoice summary",
+					"invoice", invoice.InvoiceUUID,
+					"err", err,
+				)
+				continue
+			}
+		}
+		summaries[invoice.InvoiceUUID] = summary
+
+		if err := c.collectSummary(ch, invoice, summary); err != nil {
+			c.errors.WithLabelValues("invoice").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't parse invoice summary",
+				"invoice", invoice.InvoiceUUID,
+				"err", err,
+			)
+		}
+	}
+	c.summaries = summaries
+}
+
+func (c *InvoiceCollector) collectSummary(ch chan<- prometheus.Metric, invoice godo.InvoiceListItem, summary *godo.InvoiceSummary) error {
+	for _, item := range summary.ProductCharges.Items {
+		amount, err := strconv.ParseFloat(item.Amount, 64)
+		if err != nil {
+			return fmt.Errorf("can't parse amount of %s: %w", item.Name, err)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.ProductAmount,
+			prometheus.GaugeValue,
+			amount,
+			invoice.InvoiceUUID, invoice.InvoicePeriod, item.Name,
+		)
+	}
+
+	for category, breakdown := range map[string]godo.InvoiceSummaryBreakdown{
+		"product_charges":         summary.ProductCharges,
+		"overages":                summary.Overages,
+		"taxes":                   summary.Taxes,
+		"credits_and_adjustments": summary.CreditsAndAdjustments,
+	} {
+		// Categories without any charges have no amount.
+		if breakdown.Amount == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(breakdown.Amount, 64)
+		if err != nil {
+			return fmt.Errorf("can't parse amount of %s: %w", category, err)
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.SummaryAmount,
+			prometheus.GaugeValue,
+			amount,
+			invoice.InvoiceUUID, invoice.InvoicePeriod, category,
+		)
+	}
+
+	return nil
+}
@@ -50,13 +50,19 @@ func (c *KeyCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *KeyCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	keys, _, err := c.client.Keys.List(ctx, nil)
+	keys := []godo.Key{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Keys.List(ctx, opt)
+		keys = append(keys, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("key").Add(1)
 		level.Warn(c.logger).Log(
 			"msg", "can't list keys",
 			"err", err,
 		)
+		return
 	}
 
 	for _, key := range keys {
@@ -64,13 +64,19 @@ func (c *KubernetesCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *KubernetesCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	clusters, _, err := c.client.Kubernetes.List(ctx, nil)
+	clusters := []*godo.KubernetesCluster{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Kubernetes.List(ctx, opt)
+		clusters = append(clusters, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("kubernetes").Add(1)
 		level.Warn(c.logger).Log(
 			"msg", "can't list clusters",
 			"err", err,
 		)
+		return
 	}
 
 	for _, cluster := range clusters {
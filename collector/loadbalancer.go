@@ -58,13 +58,19 @@ func (c *LoadBalancerCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	lbs, _, err := c.client.LoadBalancers.List(ctx, nil)
+	lbs := []godo.LoadBalancer{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.LoadBalancers.List(ctx, opt)
+		lbs = append(lbs, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("loadbalancer").Add(1)
 		level.Warn(c.logger).Log(
 			"msg", "can't list loadbalancers",
 			"err", err,
 		)
+		return
 	}
 
 	for _, lb := range lbs {
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/digitalocean/godo"
+)
+
+// perPage is the maximum number of items the DigitalOcean API returns per page.
+// Listing as many as possible per page keeps the number of requests low.
+const perPage = 200
+
+// paginate calls list for every page until the last page was listed.
+// list is passed the options for the page to list and
+// returns the API's response after keeping the page's items.
+func paginate(list func(opt *godo.ListOptions) (*godo.Response, error)) error {
+	opt := &godo.ListOptions{PerPage: perPage}
+
+	for {
+		resp, err := list(opt)
+		if err != nil {
+			return err
+		}
+
+		// if we are at the last page, we are done
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return nil
+		}
+
+		page, err := resp.Links.CurrentPage()
+		if err != nil {
+			return fmt.Errorf("can't read current page: %w", err)
+		}
+
+		opt.Page = page + 1
+	}
+}
+
+// total returns the total number of items of a list without listing all of them.
+// list is passed the options for a single item page and returns the API's response.
+func total(list func(opt *godo.ListOptions) (*godo.Response, error)) (int, error) {
+	resp, err := list(&godo.ListOptions{PerPage: 1})
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil || resp.Meta == nil {
+		return 0, fmt.Errorf("response has no total")
+	}
+	return resp.Meta.Total, nil
+}
+
+// pagedRoot is the root of a paginated API response.
+type pagedRoot interface {
+	pages() (*godo.Links, *godo.Meta)
+}
+
+// listPage requests a page of an API endpoint godo doesn't support yet into root.
+// The response's links and meta are set from root, so it can be used with paginate.
+func listPage(ctx context.Context, client *godo.Client, path string, opt *godo.ListOptions, root pagedRoot) (*godo.Response, error) {
+	page := opt.Page
+	if page < 1 {
+		page = 1
+	}
+	path = fmt.Sprintf("%s?page=%d&per_page=%d", path, page, opt.PerPage)
+
+	resp, err := get(ctx, client, path, root)
+	if err != nil {
+		return resp, err
+	}
+	resp.Links, resp.Meta = root.pages()
+	return resp, nil
+}
+
+// get requests an API endpoint godo doesn't support yet and decodes the response into v.
+func get(ctx context.Context, client *godo.Client, path string, v interface{}) (*godo.Response, error) {
+	req, err := client.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(ctx, req, v)
+}
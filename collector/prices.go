@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Prices are the monthly prices in dollars of resources the API doesn't report a price for.
+// Droplets and Kubernetes nodes are priced by the Sizes API instead.
+type Prices struct {
+	// VolumeGigabyte is the price of a GiB of volume storage.
+	VolumeGigabyte float64 `json:"volume_gigabyte"`
+	// SnapshotGigabyte is the price of a GiB of droplet or volume snapshots.
+	SnapshotGigabyte float64 `json:"snapshot_gigabyte"`
+	// ReservedIPv4 is the price of a reserved IPv4 that isn't assigned to a droplet.
+	// Assigned reserved IPs are free.
+	ReservedIPv4 float64 `json:"reserved_ipv4"`
+	// LoadBalancerNode is the price of a node of load balancers scaled by size unit.
+	LoadBalancerNode float64 `json:"load_balancer_node"`
+	// LoadBalancerSizes are the prices of load balancers by their size slug.
+	LoadBalancerSizes map[string]float64 `json:"load_balancer_sizes"`
+	// DatabaseSizes are the prices of a database cluster node by the cluster's size slug.
+	DatabaseSizes map[string]float64 `json:"database_sizes"`
+	// SpacesSubscription is the price of the Spaces subscription, paid once buckets exist.
+	SpacesSubscription float64 `json:"spaces_subscription"`
+	// DropletBackups is the share of the droplet's price paid for enabled backups.
+	DropletBackups float64 `json:"droplet_backups"`
+}
+
+// DefaultPrices returns DigitalOcean's list prices.
+func DefaultPrices() Prices {
+	return Prices{
+		VolumeGigabyte:   0.10,
+		SnapshotGigabyte: 0.05,
+		ReservedIPv4:     4,
+		LoadBalancerNode: 12,
+		LoadBalancerSizes: map[string]float64{
+			"lb-small":  12,
+			"lb-medium": 36,
+			"lb-large":  72,
+		},
+		DatabaseSizes: map[string]float64{
+			"db-s-1vcpu-1gb":   15,
+			"db-s-1vcpu-2gb":   30,
+			"db-s-2vcpu-4gb":   60,
+			"db-s-4vcpu-8gb":   120,
+			"db-s-6vcpu-16gb":  240,
+			"db-s-8vcpu-32gb":  480,
+			"db-s-16vcpu-64gb": 960,
+		},
+		SpacesSubscription: 5,
+		DropletBackups:     0.2,
+	}
+}
+
+// LoadPrices reads a JSON price table from path.
+// Prices missing from the file keep their default,
+// sizes in the file are added to the default sizes.
+func LoadPrices(path string) (Prices, error) {
+	prices := DefaultPrices()
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return prices, err
+	}
+	if err := json.Unmarshal(content, &prices); err != nil {
+		return prices, err
+	}
+
+	return prices, nil
+}
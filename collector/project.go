@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ProjectCollector collects metrics about projects and the resources assigned to them.
+type ProjectCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	Info            *prometheus.Desc
+	Resources       *prometheus.Desc
+	ResourceProject *prometheus.Desc
+}
+
+// NewProjectCollector returns a new ProjectCollector.
+func NewProjectCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *ProjectCollector {
+	errors.WithLabelValues("project").Add(0)
+
+	return &ProjectCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		Info: prometheus.NewDesc(
+			"digitalocean_project_info",
+			"A metric with a constant '1' value labeled by the project's name, purpose, environment and whether it's the default project",
+			[]string{"id", "name", "purpose", "environment", "is_default"}, nil,
+		),
+		Resources: prometheus.NewDesc(
+			"digitalocean_project_resources",
+			"The number of resources assigned to the project by resource type",
+			[]string{"id", "name", "type"}, nil,
+		),
+		ResourceProject: prometheus.NewDesc(
+			"digitalocean_resource_project_info",
+			"A metric with a constant '1' value labeled by a resource's URN, type and id and the project it's assigned to",
+			[]string{"urn", "type", "id", "project"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *ProjectCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.Resources
+	ch <- c.ResourceProject
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *ProjectCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	projects := []godo.Project{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Projects.List(ctx, opt)
+		projects = append(projects, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("project").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list projects",
+			"err", err,
+		)
+		return
+	}
+
+	for _, project := range projects {
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			project.ID, project.Name, project.Purpose, project.Environment, strconv.FormatBool(project.IsDefault),
+		)
+
+		resources, err := c.listResources(ctx, project.ID)
+		if err != nil {
+			c.errors.WithLabelValues("project").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't list project resources",
+				"project", project.ID,
+				"err", err,
+			)
+			continue
+		}
+
+		counts := map[string]int{}
+		for _, resource := range resources {
+			t, id := parseURN(resource.URN)
+			counts[t]++
+
+			ch <- prometheus.MustNewConstMetric(
+				c.ResourceProject,
+				prometheus.GaugeValue,
+				1.0,
+				resource.URN, t, id, project.Name,
+			)
+		}
+		for t, count := range counts {
+			ch <- prometheus.MustNewConstMetric(
+				c.Resources,
+				prometheus.GaugeValue,
+				float64(count),
+				project.ID, project.Name, t,
+			)
+		}
+	}
+}
+
+// listResources pages through all resources of the project.
+func (c *ProjectCollector) listResources(ctx context.Context, id string) ([]godo.ProjectResource, error) {
+	resources := []godo.ProjectResource{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Projects.ListResources(ctx, id, opt)
+		resources = append(resources, page...)
+		return resp, err
+	})
+	return resources, err
+}
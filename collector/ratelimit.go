@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	headerRateLimit     = "RateLimit-Limit"
+	headerRateRemaining = "RateLimit-Remaining"
+	headerRateReset     = "RateLimit-Reset"
+)
+
+// RateLimitCollector collects the API's rate limit
+// as reported by the responses to all requests of a client.
+type RateLimitCollector struct {
+	mtx       sync.Mutex
+	observed  bool
+	limit     float64
+	remaining float64
+	reset     float64
+
+	RateLimited prometheus.Counter
+
+	Limit     *prometheus.Desc
+	Remaining *prometheus.Desc
+	Reset     *prometheus.Desc
+}
+
+// NewRateLimitCollector returns a new RateLimitCollector.
+// Its Observe method has to be set as the client's request completion callback.
+func NewRateLimitCollector() *RateLimitCollector {
+	return &RateLimitCollector{
+		RateLimited: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "digitalocean_api_rate_limited_total",
+			Help: "The total number of API requests rejected with 429 Too Many Requests",
+		}),
+
+		Limit: prometheus.NewDesc(
+			"digitalocean_api_rate_limit",
+			"The number of API requests allowed per hour",
+			nil, nil,
+		),
+		Remaining: prometheus.NewDesc(
+			"digitalocean_api_rate_limit_remaining",
+			"The number of API requests remaining in the current window",
+			nil, nil,
+		),
+		Reset: prometheus.NewDesc(
+			"digitalocean_api_rate_limit_reset_timestamp_seconds",
+			"Unix timestamp when the oldest API request expires from the current window",
+			nil, nil,
+		),
+	}
+}
+
+// Observe updates the rate limit from the headers of an API response.
+// It has the signature of a godo.RequestCompletionCallback.
+func (c *RateLimitCollector) Observe(_ *http.Request, resp *http.Response) {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.RateLimited.Inc()
+	}
+
+	limit, err := strconv.ParseFloat(resp.Header.Get(headerRateLimit), 64)
+	if err != nil {
+		// Not every response, like the ones from proxies, has the rate limit headers.
+		return
+	}
+	remaining, _ := strconv.ParseFloat(resp.Header.Get(headerRateRemaining), 64)
+	reset, _ := strconv.ParseFloat(resp.Header.Get(headerRateReset), 64)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.observed = true
+	c.limit = limit
+	c.remaining = remaining
+	c.reset = reset
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *RateLimitCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.RateLimited.Desc()
+	ch <- c.Limit
+	ch <- c.Remaining
+	ch <- c.Reset
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *RateLimitCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- c.RateLimited
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Until the first response there is no rate limit to report.
+	if !c.observed {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.Limit,
+		prometheus.GaugeValue,
+		c.limit,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.Remaining,
+		prometheus.GaugeValue,
+		c.remaining,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.Reset,
+		prometheus.GaugeValue,
+		c.reset,
+	)
+}
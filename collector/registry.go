@@ -0,0 +1,207 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegistryCollector collects metrics about the account's Container Registry.
+type RegistryCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	Info                        *prometheus.Desc
+	StorageIncluded             *prometheus.Desc
+	StorageUsage                *prometheus.Desc
+	Repositories                *prometheus.Desc
+	GarbageCollectionRunning    *prometheus.Desc
+	GarbageCollectionFreed      *prometheus.Desc
+	GarbageCollectionCompletion *prometheus.Desc
+}
+
+// NewRegistryCollector returns a new RegistryCollector.
+func NewRegistryCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *RegistryCollector {
+	errors.WithLabelValues("registry").Add(0)
+
+	labels := []string{"name"}
+	return &RegistryCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		Info: prometheus.NewDesc(
+			"digitalocean_registry_info",
+			"A metric with a constant '1' value labeled by the registry's name and subscription tier",
+			[]string{"name", "tier"}, nil,
+		),
+		StorageIncluded: prometheus.NewDesc(
+			"digitalocean_registry_storage_included_bytes",
+			"Storage included in the registry's subscription tier in bytes",
+			labels, nil,
+		),
+		StorageUsage: prometheus.NewDesc(
+			"digitalocean_registry_storage_usage_bytes",
+			"Storage used by the registry in bytes",
+			labels, nil,
+		),
+		Repositories: prometheus.NewDesc(
+			"digitalocean_registry_repositories",
+			"The number of repositories in the registry",
+			labels, nil,
+		),
+		GarbageCollectionRunning: prometheus.NewDesc(
+			"digitalocean_registry_garbage_collection_running",
+			"If 1 a garbage collection of the registry is running, 0 otherwise",
+			labels, nil,
+		),
+		GarbageCollectionFreed: prometheus.NewDesc(
+			"digitalocean_registry_garbage_collection_last_freed_bytes",
+			"Bytes freed by the registry's last successful garbage collection",
+			labels, nil,
+		),
+		GarbageCollectionCompletion: prometheus.NewDesc(
+			"digitalocean_registry_garbage_collection_last_completion_timestamp_seconds",
+			"Unix timestamp of the completion of the registry's last successful garbage collection",
+			labels, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *RegistryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.StorageIncluded
+	ch <- c.StorageUsage
+	ch <- c.Repositories
+	ch <- c.GarbageCollectionRunning
+	ch <- c.GarbageCollectionFreed
+	ch <- c.GarbageCollectionCompletion
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *RegistryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	registry, _, err := c.client.Registry.Get(ctx)
+	if err != nil {
+		// Accounts without a registry aren't an error.
+		var errResp *godo.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+			return
+		}
+		c.errors.WithLabelValues("registry").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't get registry",
+			"err", err,
+		)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.StorageUsage,
+		prometheus.GaugeValue,
+		float64(registry.StorageUsageBytes),
+		registry.Name,
+	)
+
+	subscription, _, err := c.client.Registry.GetSubscription(ctx)
+	if err != nil {
+		c.errors.WithLabelValues("registry").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't get registry subscription",
+			"err", err,
+		)
+	} else if subscription.Tier != nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			registry.Name, subscription.Tier.Slug,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.StorageIncluded,
+			prometheus.GaugeValue,
+			float64(subscription.Tier.IncludedStorageBytes),
+			registry.Name,
+		)
+	}
+
+	repositories := 0
+	err = paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Registry.ListRepositories(ctx, registry.Name, opt)
+		repositories += len(page)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("registry").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list registry repositories",
+			"err", err,
+		)
+	} else {
+		ch <- prometheus.MustNewConstMetric(
+			c.Repositories,
+			prometheus.GaugeValue,
+			float64(repositories),
+			registry.Name,
+		)
+	}
+
+	// Garbage collections pile up over time, the first page has the most recent ones.
+	gcs, _, err := c.client.Registry.ListGarbageCollections(ctx, registry.Name, &godo.ListOptions{PerPage: perPage})
+	if err != nil {
+		c.errors.WithLabelValues("registry").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list registry garbage collections",
+			"err", err,
+		)
+		return
+	}
+
+	running := 0.0
+	var last *godo.GarbageCollection
+	for _, gc := range gcs {
+		switch gc.Status {
+		case "succeeded":
+			if last == nil || gc.UpdatedAt.After(last.UpdatedAt) {
+				last = gc
+			}
+		case "failed", "cancelled":
+		default:
+			running = 1
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.GarbageCollectionRunning,
+		prometheus.GaugeValue,
+		running,
+		registry.Name,
+	)
+	if last != nil {
+		ch <- prometheus.MustNewConstMetric(
+			c.GarbageCollectionFreed,
+			prometheus.GaugeValue,
+			float64(last.FreedBytes),
+			registry.Name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GarbageCollectionCompletion,
+			prometheus.GaugeValue,
+			float64(last.UpdatedAt.Unix()),
+			registry.Name,
+		)
+	}
+}
@@ -0,0 +1,209 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The godo version in use predates reserved IPs, so they are requested directly.
+const (
+	reservedIPv4Path = "v2/reserved_ips"
+	reservedIPv6Path = "v2/reserved_ipv6"
+)
+
+type reservedIPv4 struct {
+	IP        string        `json:"ip"`
+	Region    *godo.Region  `json:"region"`
+	Droplet   *godo.Droplet `json:"droplet"`
+	ProjectID string        `json:"project_id"`
+}
+
+type reservedIPv4sRoot struct {
+	ReservedIPs []reservedIPv4 `json:"reserved_ips"`
+	Links       *godo.Links    `json:"links"`
+	Meta        *godo.Meta     `json:"meta"`
+}
+
+func (r *reservedIPv4sRoot) pages() (*godo.Links, *godo.Meta) { return r.Links, r.Meta }
+
+type reservedIPv6 struct {
+	IP         string        `json:"ip"`
+	RegionSlug string        `json:"region_slug"`
+	Droplet    *godo.Droplet `json:"droplet"`
+}
+
+type reservedIPv6sRoot struct {
+	ReservedIPv6s []reservedIPv6 `json:"reserved_ipv6s"`
+	Links         *godo.Links    `json:"links"`
+	Meta          *godo.Meta     `json:"meta"`
+}
+
+func (r *reservedIPv6sRoot) pages() (*godo.Links, *godo.Meta) { return r.Links, r.Meta }
+
+// reservedIP is a reserved IP of either family.
+type reservedIP struct {
+	ip        string
+	family    string
+	region    string
+	projectID string
+	droplet   *godo.Droplet
+}
+
+// dropletID returns the id of the droplet the IP is assigned to, empty if unassigned.
+func (ip reservedIP) dropletID() string {
+	if ip.droplet == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", ip.droplet.ID)
+}
+
+// ReservedIPCollector collects metrics about all reserved IPv4 and IPv6 addresses.
+// It remembers the last droplet every IP was assigned to, to count reassignments between scrapes.
+type ReservedIPCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	mtx sync.Mutex
+	// assigned has the IPs of the last scrape with the last droplet they were assigned to.
+	assigned map[string]reservedIP
+
+	Reassignments *prometheus.CounterVec
+
+	Active *prometheus.Desc
+}
+
+// NewReservedIPCollector returns a new ReservedIPCollector.
+func NewReservedIPCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *ReservedIPCollector {
+	errors.WithLabelValues("reserved_ip").Add(0)
+
+	return &ReservedIPCollector{
+		logger:   logger,
+		errors:   errors,
+		client:   client,
+		timeout:  timeout,
+		assigned: map[string]reservedIP{},
+
+		Reassignments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "digitalocean_reserved_ip_reassignments_total",
+			Help: "The total number of times the reserved ip moved from one droplet to a different droplet",
+		}, []string{"ip", "family"}),
+
+		Active: prometheus.NewDesc(
+			"digitalocean_reserved_ip_active",
+			"If 1 the reserved ip is assigned to a droplet, 0 otherwise",
+			[]string{"ip", "family", "region", "project_id", "droplet_id", "droplet_name"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *ReservedIPCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.Reassignments.Describe(ch)
+	ch <- c.Active
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *ReservedIPCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	ips, err := listReservedIPs(ctx, c.client)
+	if err != nil {
+		c.errors.WithLabelValues("reserved_ip").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list reserved ips",
+			"err", err,
+		)
+		c.Reassignments.Collect(ch)
+		return
+	}
+
+	assigned := make(map[string]reservedIP, len(ips))
+	for _, ip := range ips {
+		var active float64
+		var dropletName string
+		if ip.droplet != nil {
+			active = 1
+			dropletName = ip.droplet.Name
+		}
+		dropletID := ip.dropletID()
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Active,
+			prometheus.GaugeValue,
+			active,
+			ip.ip, ip.family, ip.region, ip.projectID, dropletID, dropletName,
+		)
+
+		reassignments := c.Reassignments.WithLabelValues(ip.ip, ip.family)
+		// Only moves from one droplet to another are reassignments, assigning and unassigning an IP aren't.
+		// IPs seen for the first time have no previous droplet, they are new or it's the first scrape.
+		previous := c.assigned[ip.ip]
+		switch {
+		case ip.droplet == nil:
+			// Remember the last droplet, so moving the IP to another droplet via being unassigned is counted too.
+			ip.droplet = previous.droplet
+		case previous.droplet != nil && previous.dropletID() != dropletID:
+			reassignments.Inc()
+		}
+		assigned[ip.ip] = ip
+	}
+
+	// Released IPs don't need to be reported anymore.
+	for _, ip := range c.assigned {
+		if _, ok := assigned[ip.ip]; !ok {
+			c.Reassignments.DeleteLabelValues(ip.ip, ip.family)
+		}
+	}
+	c.assigned = assigned
+
+	c.Reassignments.Collect(ch)
+}
+
+// listReservedIPs pages through the reserved IPs of both families.
+func listReservedIPs(ctx context.Context, client *godo.Client) ([]reservedIP, error) {
+	ips := []reservedIP{}
+
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		root := new(reservedIPv4sRoot)
+		resp, err := listPage(ctx, client, reservedIPv4Path, opt, root)
+		for _, ip := range root.ReservedIPs {
+			var region string
+			if ip.Region != nil {
+				region = ip.Region.Slug
+			}
+			ips = append(ips, reservedIP{ip: ip.IP, family: "ipv4", region: region, projectID: ip.ProjectID, droplet: ip.Droplet})
+		}
+		return resp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't list reserved ipv4s: %w", err)
+	}
+
+	err = paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		root := new(reservedIPv6sRoot)
+		resp, err := listPage(ctx, client, reservedIPv6Path, opt, root)
+		for _, ip := range root.ReservedIPv6s {
+			ips = append(ips, reservedIP{ip: ip.IP, family: "ipv6", region: ip.RegionSlug, droplet: ip.Droplet})
+		}
+		return resp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't list reserved ipv6s: %w", err)
+	}
+
+	return ips, nil
+}
@@ -0,0 +1,122 @@
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// InstrumentedCollector collects another collector and reports
+// how long collecting took and whether it succeeded.
+// Collecting failed if the collector counted any errors while collecting.
+type InstrumentedCollector struct {
+	logger    log.Logger
+	name      string
+	collector prometheus.Collector
+	errors    prometheus.Counter
+
+	// mtx makes sure only one scrape collects at a time,
+	// otherwise the errors of one scrape could fail another.
+	mtx         sync.Mutex
+	lastSuccess time.Time
+
+	Duration    *prometheus.Desc
+	Success     *prometheus.Desc
+	LastSuccess *prometheus.Desc
+}
+
+// NewInstrumentedCollector returns a new InstrumentedCollector.
+// errors has to be the counter the collector increments on errors.
+func NewInstrumentedCollector(logger log.Logger, name string, collector prometheus.Collector, errors prometheus.Counter) *InstrumentedCollector {
+	labels := prometheus.Labels{"collector": name}
+	return &InstrumentedCollector{
+		logger:    logger,
+		name:      name,
+		collector: collector,
+		errors:    errors,
+
+		Duration: prometheus.NewDesc(
+			"digitalocean_scrape_collector_duration_seconds",
+			"Duration of a collector scrape in seconds",
+			nil, labels,
+		),
+		Success: prometheus.NewDesc(
+			"digitalocean_scrape_collector_success",
+			"If 1 the collector scrape succeeded, 0 otherwise",
+			nil, labels,
+		),
+		LastSuccess: prometheus.NewDesc(
+			"digitalocean_scrape_collector_last_success_timestamp_seconds",
+			"Unix timestamp of the last successful collector scrape",
+			nil, labels,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *InstrumentedCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.collector.Describe(ch)
+	ch <- c.Duration
+	ch <- c.Success
+	ch <- c.LastSuccess
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *InstrumentedCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	errorsBefore := counterValue(c.errors)
+	start := time.Now()
+	c.collector.Collect(ch)
+	duration := time.Since(start)
+
+	var success float64
+	if counterValue(c.errors) == errorsBefore {
+		success = 1
+		c.lastSuccess = start
+	} else {
+		level.Debug(c.logger).Log(
+			"msg", "collector scrape failed",
+			"collector", c.name,
+			"duration", duration,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.Duration,
+		prometheus.GaugeValue,
+		duration.Seconds(),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.Success,
+		prometheus.GaugeValue,
+		success,
+	)
+	if !c.lastSuccess.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			c.LastSuccess,
+			prometheus.GaugeValue,
+			float64(c.lastSuccess.Unix()),
+		)
+	}
+}
+
+// instruments returns whether desc describes one of the metrics reporting on the collection.
+func (c *InstrumentedCollector) instruments(desc *prometheus.Desc) bool {
+	return desc == c.Duration || desc == c.Success || desc == c.LastSuccess
+}
+
+// counterValue returns the current value of the counter.
+func counterValue(counter prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := counter.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+)
+
+func TestInstrumentedCollector(t *testing.T) {
+	errors := newErrors().WithLabelValues("test")
+	inner := newTestCollector(errors, 1)
+	c := NewInstrumentedCollector(log.NewNopLogger(), "test", inner, errors)
+
+	values := gatherValues(t, c)
+	if values["test_value"] != 1 {
+		t.Errorf("expected the collector's test_value 1, got %v", values["test_value"])
+	}
+	if _, ok := values["digitalocean_scrape_collector_duration_seconds"]; !ok {
+		t.Errorf("expected the scrape duration, got %v", values)
+	}
+	if values["digitalocean_scrape_collector_success"] != 1 {
+		t.Errorf("expected success 1, got %v", values["digitalocean_scrape_collector_success"])
+	}
+	if got := values["digitalocean_scrape_collector_last_success_timestamp_seconds"]; got != float64(c.lastSuccess.Unix()) || got == 0 {
+		t.Errorf("expected the last success timestamp %d, got %v", c.lastSuccess.Unix(), got)
+	}
+
+	// Pretend the success happened earlier, to tell whether the timestamp moves.
+	c.lastSuccess = c.lastSuccess.Add(-time.Minute)
+	previousSuccess := c.lastSuccess
+
+	inner.set(2, true)
+	values = gatherValues(t, c)
+	if values["digitalocean_scrape_collector_success"] != 0 {
+		t.Errorf("expected success 0 after an error, got %v", values["digitalocean_scrape_collector_success"])
+	}
+	if _, ok := values["digitalocean_scrape_collector_duration_seconds"]; !ok {
+		t.Errorf("expected the scrape duration of a failed scrape, got %v", values)
+	}
+	if got := values["digitalocean_scrape_collector_last_success_timestamp_seconds"]; got != float64(previousSuccess.Unix()) {
+		t.Errorf("expected a failed scrape to keep the last success timestamp %d, got %v", previousSuccess.Unix(), got)
+	}
+
+	inner.set(3, false)
+	values = gatherValues(t, c)
+	if values["digitalocean_scrape_collector_success"] != 1 {
+		t.Errorf("expected success 1 again, got %v", values["digitalocean_scrape_collector_success"])
+	}
+	if got := values["digitalocean_scrape_collector_last_success_timestamp_seconds"]; got <= float64(previousSuccess.Unix()) {
+		t.Errorf("expected the last success timestamp to move past %d, got %v", previousSuccess.Unix(), got)
+	}
+}
+
+func TestInstrumentedCollectorNeverSucceeded(t *testing.T) {
+	errors := newErrors().WithLabelValues("test")
+	inner := newTestCollector(errors, 1)
+	inner.set(1, true)
+	c := NewInstrumentedCollector(log.NewNopLogger(), "test", inner, errors)
+
+	values := gatherValues(t, c)
+	if _, ok := values["digitalocean_scrape_collector_last_success_timestamp_seconds"]; ok {
+		t.Errorf("expected no last success timestamp before the first success, got %v", values)
+	}
+}
@@ -49,13 +49,19 @@ func NewSnapshotCollector(logger log.Logger, errors *prometheus.CounterVec, clie
 // collected by this Collector.
 func (c *SnapshotCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Size
+	ch <- c.MinDiskSize
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
 func (c *SnapshotCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	snapshots, _, err := c.client.Snapshots.List(ctx, nil)
+	snapshots := []godo.Snapshot{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Snapshots.List(ctx, opt)
+		snapshots = append(snapshots, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("snapshot").Add(1)
 		level.Warn(c.logger).Log(
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
 	"time"
 
@@ -18,31 +19,33 @@ import (
 
 // SpacesCollector collects metrics about all spaces buckets.
 type SpacesCollector struct {
-	logger          log.Logger
-	errors          *prometheus.CounterVec
-	client          *godo.Client
-	timeout         time.Duration
-	accessKeyID     string
-	accessKeySecret string
-	Bucket          *prometheus.Desc
-	BucketCreated   *prometheus.Desc
+	spacesLister
+
+	logger        log.Logger
+	errors        *prometheus.CounterVec
+	timeout       time.Duration
+	Bucket        *prometheus.Desc
+	BucketCreated *prometheus.Desc
 }
 
 // Templated since each region has a different endpoint
 const spacesDomain = "%s.digitaloceanspaces.com"
 
+// spacesEndpoint returns the Spaces endpoint of a region.
+func spacesEndpoint(region string) string {
+	return fmt.Sprintf(spacesDomain, region)
+}
+
 // SpacesCollector returns a new SpacesCollector.
 func NewSpacesCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, accessKeyID string, accessKeySecret string, timeout time.Duration) *SpacesCollector {
 	errors.WithLabelValues("spaces_bucket").Add(0)
 
 	labels := []string{"region", "name"}
 	return &SpacesCollector{
-		logger:          logger,
-		errors:          errors,
-		client:          client,
-		timeout:         timeout,
-		accessKeyID:     accessKeyID,
-		accessKeySecret: accessKeySecret,
+		spacesLister: newSpacesLister(client, accessKeyID, accessKeySecret),
+		logger:       logger,
+		errors:       errors,
+		timeout:      timeout,
 		Bucket: prometheus.NewDesc(
 			"digitalocean_spaces_bucket",
 			"Spaces bucket and its details. Will always be 1 if exists",
@@ -60,6 +63,7 @@ func NewSpacesCollector(logger log.Logger, errors *prometheus.CounterVec, client
 // collected by this Collector.
 func (c *SpacesCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Bucket
+	ch <- c.BucketCreated
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
@@ -67,41 +71,98 @@ func (c *SpacesCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
 
-	regions, _, err := c.client.Regions.List(ctx, nil)
-	if err != nil {
+	buckets, errs := c.listBuckets(ctx)
+	for _, err := range errs {
 		c.errors.WithLabelValues("spaces_bucket").Add(1)
 		level.Warn(c.logger).Log(
-			"msg", "can't list regions",
+			"msg", "can't list spaces buckets",
 			"err", err,
 		)
-		return
 	}
 
+	for _, bucket := range buckets {
+		labels := []string{
+			bucket.Region,
+			bucket.Name,
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Bucket,
+			prometheus.GaugeValue,
+			1.0,
+			labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.BucketCreated,
+			prometheus.CounterValue,
+			float64(bucket.CreationDate.Unix()),
+			labels...,
+		)
+	}
+}
+
+// spacesBucket is a Spaces bucket and the region it was listed in.
+type spacesBucket struct {
+	minio.BucketInfo
+	Region string
+}
+
+// spacesLister lists the Spaces buckets of all regions.
+// It's shared by all collectors that need to know about buckets.
+type spacesLister struct {
+	client          *godo.Client
+	accessKeyID     string
+	accessKeySecret string
+	// transport is used for the requests to Spaces, if nil minio's default transport is used.
+	transport http.RoundTripper
+	secure    bool
+}
+
+func newSpacesLister(client *godo.Client, accessKeyID string, accessKeySecret string) spacesLister {
+	return spacesLister{
+		client:          client,
+		accessKeyID:     accessKeyID,
+		accessKeySecret: accessKeySecret,
+		secure:          true,
+	}
+}
+
+// listBuckets returns the buckets of all regions.
+// Regions that fail don't stop the others from being listed,
+// all of their errors are returned next to the buckets that could be listed.
+func (l spacesLister) listBuckets(ctx context.Context) ([]spacesBucket, []error) {
+	regions, _, err := l.client.Regions.List(ctx, nil)
+	if err != nil {
+		return nil, []error{fmt.Errorf("can't list regions: %w", err)}
+	}
+
+	var (
+		mtx     sync.Mutex
+		buckets []spacesBucket
+		errs    []error
+	)
+
 	// The spaces API can be slow when checking each region 1 by 1, speed up by running them concurrently
 	wg := sync.WaitGroup{}
-	defer wg.Wait()
-
 	for _, region := range regions {
 		wg.Add(1)
 		go func(region godo.Region) {
 			defer wg.Done()
-			spacesEndpoint := fmt.Sprintf(spacesDomain, region.Slug)
-
-			spacesClient, err := minio.New(spacesEndpoint, &minio.Options{
-				Creds:  credentials.NewStaticV4(c.accessKeyID, c.accessKeySecret, ""),
-				Secure: true,
+			spacesClient, err := minio.New(spacesEndpoint(region.Slug), &minio.Options{
+				Creds:     credentials.NewStaticV4(l.accessKeyID, l.accessKeySecret, ""),
+				Secure:    l.secure,
+				Transport: l.transport,
 			})
 			if err != nil {
-				c.errors.WithLabelValues("spaces_bucket").Add(1)
-				level.Warn(c.logger).Log(
-					"msg", "can't create minio client",
-					"err", err,
-				)
+				mtx.Lock()
+				errs = append(errs, fmt.Errorf("can't create minio client for %s: %w", region.Slug, err))
+				mtx.Unlock()
 				return
 			}
 
 			// Use a separate context than the godo client. The spaces API can be a bit slow
-			buckets, err := spacesClient.ListBuckets(context.Background())
+			infos, err := spacesClient.ListBuckets(context.Background())
 			if err != nil {
 				// Not all regions may support spaces
 				// Let's not log all of the known failures
@@ -109,34 +170,20 @@ func (c *SpacesCollector) Collect(ch chan<- prometheus.Metric) {
 				if errors.As(err, &dnsError) {
 					return
 				}
-				c.errors.WithLabelValues("spaces_bucket").Add(1)
-				level.Warn(c.logger).Log(
-					"msg", "can't list spaces buckets",
-					"err", err,
-				)
+				mtx.Lock()
+				errs = append(errs, fmt.Errorf("can't list buckets in %s: %w", region.Slug, err))
+				mtx.Unlock()
 				return
 			}
 
-			for _, bucket := range buckets {
-				labels := []string{
-					region.Slug,
-					bucket.Name,
-				}
-
-				ch <- prometheus.MustNewConstMetric(
-					c.Bucket,
-					prometheus.GaugeValue,
-					1.0,
-					labels...,
-				)
-
-				ch <- prometheus.MustNewConstMetric(
-					c.BucketCreated,
-					prometheus.CounterValue,
-					float64(bucket.CreationDate.Unix()),
-					labels...,
-				)
+			mtx.Lock()
+			defer mtx.Unlock()
+			for _, info := range infos {
+				buckets = append(buckets, spacesBucket{BucketInfo: info, Region: region.Slug})
 			}
 		}(region)
 	}
+	wg.Wait()
+
+	return buckets, errs
 }
@@ -0,0 +1,31 @@
+package collector
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// stateMetrics sends a series for every known state, 1 for the current state and 0 for all others.
+// The state is the last label of desc, following labels.
+// A current state that isn't known is sent with 1 as well, so it isn't lost until it's added to states.
+func stateMetrics(ch chan<- prometheus.Metric, desc *prometheus.Desc, states []string, current string, labels ...string) {
+	known := false
+	for _, state := range states {
+		value := 0.0
+		if current == state {
+			value = 1
+			known = true
+		}
+		ch <- prometheus.MustNewConstMetric(
+			desc,
+			prometheus.GaugeValue,
+			value,
+			append(labels, state)...,
+		)
+	}
+	if !known && current != "" {
+		ch <- prometheus.MustNewConstMetric(
+			desc,
+			prometheus.GaugeValue,
+			1.0,
+			append(labels, current)...,
+		)
+	}
+}
@@ -0,0 +1,103 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TagCollector collects metrics about tags and the resources they are attached to.
+type TagCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	Resources *prometheus.Desc
+}
+
+// NewTagCollector returns a new TagCollector.
+func NewTagCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *TagCollector {
+	errors.WithLabelValues("tag").Add(0)
+
+	return &TagCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		Resources: prometheus.NewDesc(
+			"digitalocean_tag_resources",
+			"The number of resources the tag is attached to by resource type",
+			[]string{"tag", "resource_type"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *TagCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Resources
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *TagCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	tags := []godo.Tag{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Tags.List(ctx, opt)
+		tags = append(tags, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("tag").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list tags",
+			"err", err,
+		)
+		return
+	}
+
+	for _, tag := range tags {
+		// Types the tag isn't attached to are missing from the response, they are 0.
+		counts := map[string]int{
+			"droplet":         0,
+			"image":           0,
+			"volume":          0,
+			"volume_snapshot": 0,
+			"database":        0,
+		}
+		if r := tag.Resources; r != nil {
+			if r.Droplets != nil {
+				counts["droplet"] = r.Droplets.Count
+			}
+			if r.Images != nil {
+				counts["image"] = r.Images.Count
+			}
+			if r.Volumes != nil {
+				counts["volume"] = r.Volumes.Count
+			}
+			if r.VolumeSnapshots != nil {
+				counts["volume_snapshot"] = r.VolumeSnapshots.Count
+			}
+			if r.Databases != nil {
+				counts["database"] = r.Databases.Count
+			}
+		}
+
+		for resourceType, count := range counts {
+			ch <- prometheus.MustNewConstMetric(
+				c.Resources,
+				prometheus.GaugeValue,
+				float64(count),
+				tag.Name, resourceType,
+			)
+		}
+	}
+}
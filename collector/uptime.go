@@ -0,0 +1,241 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The godo version in use predates uptime checks, so they are requested directly.
+const uptimeChecksPath = "v2/uptime/checks"
+
+type uptimeCheck struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Target  string   `json:"target"`
+	Regions []string `json:"regions"`
+	Enabled bool     `json:"enabled"`
+}
+
+type uptimeChecksRoot struct {
+	Checks []uptimeCheck `json:"checks"`
+	Links  *godo.Links   `json:"links"`
+	Meta   *godo.Meta    `json:"meta"`
+}
+
+func (r *uptimeChecksRoot) pages() (*godo.Links, *godo.Meta) { return r.Links, r.Meta }
+
+type uptimeRegionState struct {
+	Status                    string    `json:"status"`
+	StatusChangedAt           time.Time `json:"status_changed_at"`
+	ThirtyDayUptimePercentage float64   `json:"thirty_day_uptime_percentage"`
+}
+
+type uptimeStateRoot struct {
+	State struct {
+		Regions map[string]uptimeRegionState `json:"regions"`
+	} `json:"state"`
+}
+
+type uptimeAlert struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Threshold  int    `json:"threshold"`
+	Comparison string `json:"comparison"`
+	Period     string `json:"period"`
+}
+
+type uptimeAlertsRoot struct {
+	Alerts []uptimeAlert `json:"alerts"`
+	Links  *godo.Links   `json:"links"`
+	Meta   *godo.Meta    `json:"meta"`
+}
+
+func (r *uptimeAlertsRoot) pages() (*godo.Links, *godo.Meta) { return r.Links, r.Meta }
+
+// UptimeCollector collects metrics about Uptime checks, their state in every probing region and their alerts.
+// The API only reports whether a check is up, not the latency or certificate expiry it measured.
+type UptimeCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	Info            *prometheus.Desc
+	Enabled         *prometheus.Desc
+	Up              *prometheus.Desc
+	StatusChanged   *prometheus.Desc
+	ThirtyDayUptime *prometheus.Desc
+	AlertThreshold  *prometheus.Desc
+}
+
+// NewUptimeCollector returns a new UptimeCollector.
+func NewUptimeCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *UptimeCollector {
+	errors.WithLabelValues("uptime").Add(0)
+
+	labels := []string{"id", "name"}
+	regionLabels := []string{"id", "name", "region"}
+	return &UptimeCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		Info: prometheus.NewDesc(
+			"digitalocean_uptime_check_info",
+			"A metric with a constant '1' value labeled by the uptime check's type and target",
+			[]string{"id", "name", "type", "target"}, nil,
+		),
+		Enabled: prometheus.NewDesc(
+			"digitalocean_uptime_check_enabled",
+			"If 1 the uptime check is enabled, 0 otherwise",
+			labels, nil,
+		),
+		Up: prometheus.NewDesc(
+			"digitalocean_uptime_check_up",
+			"If 1 the uptime check's target is up as probed from the region, 0 otherwise",
+			regionLabels, nil,
+		),
+		StatusChanged: prometheus.NewDesc(
+			"digitalocean_uptime_check_status_changed_timestamp_seconds",
+			"Unix timestamp of the last time the uptime check's status changed in the region",
+			regionLabels, nil,
+		),
+		ThirtyDayUptime: prometheus.NewDesc(
+			"digitalocean_uptime_check_thirty_day_uptime_ratio",
+			"Ratio of the last 30 days the uptime check's target was up as probed from the region",
+			regionLabels, nil,
+		),
+		AlertThreshold: prometheus.NewDesc(
+			"digitalocean_uptime_alert_threshold",
+			"The threshold of the uptime check's alert, in ms for latency and days for ssl_expiry alerts",
+			[]string{"check_id", "id", "name", "type", "comparison", "period"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *UptimeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.Enabled
+	ch <- c.Up
+	ch <- c.StatusChanged
+	ch <- c.ThirtyDayUptime
+	ch <- c.AlertThreshold
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *UptimeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	checks := []uptimeCheck{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		root := new(uptimeChecksRoot)
+		resp, err := listPage(ctx, c.client, uptimeChecksPath, opt, root)
+		checks = append(checks, root.Checks...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("uptime").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list uptime checks",
+			"err", err,
+		)
+		return
+	}
+
+	for _, check := range checks {
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			check.ID, check.Name, check.Type, check.Target,
+		)
+
+		enabled := 0.0
+		if check.Enabled {
+			enabled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Enabled,
+			prometheus.GaugeValue,
+			enabled,
+			check.ID, check.Name,
+		)
+
+		if err := c.collectCheck(ctx, ch, check); err != nil {
+			c.errors.WithLabelValues("uptime").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't get uptime check",
+				"check", check.ID,
+				"err", err,
+			)
+		}
+	}
+}
+
+// collectCheck collects the state and alerts of a single check.
+func (c *UptimeCollector) collectCheck(ctx context.Context, ch chan<- prometheus.Metric, check uptimeCheck) error {
+	state := new(uptimeStateRoot)
+	if _, err := get(ctx, c.client, fmt.Sprintf("%s/%s/state", uptimeChecksPath, check.ID), state); err != nil {
+		return fmt.Errorf("can't get state: %w", err)
+	}
+
+	for region, s := range state.State.Regions {
+		up := 0.0
+		if s.Status == "UP" {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.Up,
+			prometheus.GaugeValue,
+			up,
+			check.ID, check.Name, region,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.ThirtyDayUptime,
+			prometheus.GaugeValue,
+			s.ThirtyDayUptimePercentage/100,
+			check.ID, check.Name, region,
+		)
+		if !s.StatusChangedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				c.StatusChanged,
+				prometheus.GaugeValue,
+				float64(s.StatusChangedAt.Unix()),
+				check.ID, check.Name, region,
+			)
+		}
+	}
+
+	alerts := []uptimeAlert{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		root := new(uptimeAlertsRoot)
+		resp, err := listPage(ctx, c.client, fmt.Sprintf("%s/%s/alerts", uptimeChecksPath, check.ID), opt, root)
+		alerts = append(alerts, root.Alerts...)
+		return resp, err
+	})
+	if err != nil {
+		return fmt.Errorf("can't list alerts: %w", err)
+	}
+
+	for _, alert := range alerts {
+		ch <- prometheus.MustNewConstMetric(
+			c.AlertThreshold,
+			prometheus.GaugeValue,
+			float64(alert.Threshold),
+			check.ID, alert.ID, alert.Name, alert.Type, alert.Comparison, alert.Period,
+		)
+	}
+
+	return nil
+}
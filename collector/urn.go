@@ -0,0 +1,12 @@
+package collector
+
+import "strings"
+
+// parseURN returns the resource type and id of a URN like do:droplet:13457723.
+func parseURN(urn string) (string, string) {
+	parts := strings.SplitN(urn, ":", 3)
+	if len(parts) < 3 {
+		return "unknown", urn
+	}
+	return parts[1], parts[2]
+}
@@ -49,7 +49,12 @@ func (c *VolumeCollector) Describe(ch chan<- *prometheus.Desc) {
 func (c *VolumeCollector) Collect(ch chan<- prometheus.Metric) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
 	defer cancel()
-	volumes, _, err := c.client.Storage.ListVolumes(ctx, nil)
+	volumes := []godo.Volume{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{ListOptions: opt})
+		volumes = append(volumes, page...)
+		return resp, err
+	})
 	if err != nil {
 		c.errors.WithLabelValues("volume").Add(1)
 		level.Warn(c.logger).Log(
@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// vpcMemberTypes are the types of all resources that can be in a VPC, as named in their URNs.
+var vpcMemberTypes = []string{"dbaas", "droplet", "kubernetes", "loadbalancer"}
+
+// VPCCollector collects metrics about VPCs and their members.
+type VPCCollector struct {
+	logger  log.Logger
+	errors  *prometheus.CounterVec
+	client  *godo.Client
+	timeout time.Duration
+
+	Info    *prometheus.Desc
+	Members *prometheus.Desc
+}
+
+// NewVPCCollector returns a new VPCCollector.
+func NewVPCCollector(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, timeout time.Duration) *VPCCollector {
+	errors.WithLabelValues("vpc").Add(0)
+
+	return &VPCCollector{
+		logger:  logger,
+		errors:  errors,
+		client:  client,
+		timeout: timeout,
+
+		Info: prometheus.NewDesc(
+			"digitalocean_vpc_info",
+			"A metric with a constant '1' value labeled by the VPC's id, name, region, ip range and whether it's the region's default",
+			[]string{"id", "name", "region", "ip_range", "default"}, nil,
+		),
+		Members: prometheus.NewDesc(
+			"digitalocean_vpc_members",
+			"The number of resources in the VPC by resource type",
+			[]string{"id", "name", "type"}, nil,
+		),
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector.
+func (c *VPCCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Info
+	ch <- c.Members
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *VPCCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	vpcs := []*godo.VPC{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.VPCs.List(ctx, opt)
+		vpcs = append(vpcs, page...)
+		return resp, err
+	})
+	if err != nil {
+		c.errors.WithLabelValues("vpc").Add(1)
+		level.Warn(c.logger).Log(
+			"msg", "can't list vpcs",
+			"err", err,
+		)
+		return
+	}
+
+	for _, vpc := range vpcs {
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			vpc.ID, vpc.Name, vpc.RegionSlug, vpc.IPRange, strconv.FormatBool(vpc.Default),
+		)
+
+		members, err := c.listMembers(ctx, vpc.ID)
+		if err != nil {
+			c.errors.WithLabelValues("vpc").Add(1)
+			level.Warn(c.logger).Log(
+				"msg", "can't list vpc members",
+				"vpc", vpc.ID,
+				"err", err,
+			)
+			continue
+		}
+
+		// Report every known type, so that VPCs without such resources have a 0 instead of no series.
+		counts := make(map[string]int, len(vpcMemberTypes))
+		for _, t := range vpcMemberTypes {
+			counts[t] = 0
+		}
+		for _, member := range members {
+			t, _ := parseURN(member.URN)
+			counts[t]++
+		}
+		for t, count := range counts {
+			ch <- prometheus.MustNewConstMetric(
+				c.Members,
+				prometheus.GaugeValue,
+				float64(count),
+				vpc.ID, vpc.Name, t,
+			)
+		}
+	}
+}
+
+// listMembers pages through all members of the VPC.
+func (c *VPCCollector) listMembers(ctx context.Context, id string) ([]*godo.VPCMember, error) {
+	members := []*godo.VPCMember{}
+	err := paginate(func(opt *godo.ListOptions) (*godo.Response, error) {
+		page, resp, err := c.client.VPCs.ListMembers(ctx, id, nil, opt)
+		members = append(members, page...)
+		return resp, err
+	})
+	return members, err
+}
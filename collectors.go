@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	collectorFlagPrefix   = "--collector."
+	noCollectorFlagPrefix = "--no-collector."
+)
+
+// defaultCollectors has all collectors that can be enabled or disabled
+// and whether they are enabled without any flags.
+var defaultCollectors = map[string]bool{
+	"account":      true,
+	"alert_policy": true,
+	"app":          true,
+	"balance":      true,
+	"catalog":      true,
+	"cdn":          true,
+	"certificate":  true,
+	"database":     true,
+	"domain":       true,
+	"droplet":      true,
+	"firewall":     true,
+	"floating_ip":  true,
+	"image":        true,
+	"incidents":    true,
+	"invoice":      true,
+	"key":          true,
+	"kubernetes":   true,
+	"loadbalancer": true,
+	"project":      true,
+	"registry":     true,
+	"reserved_ip":  true,
+	"snapshot":     true,
+	"spaces":       true,
+	"tag":          true,
+	"uptime":       true,
+	"volume":       true,
+	"vpc":          true,
+
+	// droplet_utilization makes multiple requests per droplet, which quickly uses up the rate limit.
+	"droplet_utilization": false,
+	// droplet_backup makes up to two requests per droplet on top of listing them.
+	"droplet_backup": false,
+	// cost lists all billable resources again, it should be refreshed in the background when enabled.
+	"cost": false,
+}
+
+// collectorNames returns the names of all collectors sorted.
+func collectorNames() []string {
+	names := make([]string, 0, len(defaultCollectors))
+	for name := range defaultCollectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseCollectorFlags removes all --collector.<name> and --no-collector.<name> flags from args.
+// Both accept an optional boolean value like --collector.<name>=false, the last flag for a collector wins.
+// It returns the remaining args and which collectors are enabled after applying the flags.
+func parseCollectorFlags(args []string) ([]string, map[string]bool, error) {
+	enabled := make(map[string]bool, len(defaultCollectors))
+	for name, e := range defaultCollectors {
+		enabled[name] = e
+	}
+
+	rest := make([]string, 0, len(args))
+	for _, a := range args {
+		var name string
+		var enable bool
+		switch {
+		case strings.HasPrefix(a, collectorFlagPrefix):
+			name, enable = strings.TrimPrefix(a, collectorFlagPrefix), true
+		case strings.HasPrefix(a, noCollectorFlagPrefix):
+			name, enable = strings.TrimPrefix(a, noCollectorFlagPrefix), false
+		default:
+			rest = append(rest, a)
+			continue
+		}
+
+		if i := strings.Index(name, "="); i >= 0 {
+			value, err := strconv.ParseBool(name[i+1:])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid value in %s: %w", a, err)
+			}
+			name, enable = name[:i], enable == value
+		}
+
+		if _, ok := defaultCollectors[name]; !ok {
+			return nil, nil, fmt.Errorf("unknown collector %q in %s, available: %s", name, a, strings.Join(collectorNames(), ", "))
+		}
+		enabled[name] = enable
+	}
+
+	return rest, enabled, nil
+}
+
+// enabledCollectorNames returns the names of the enabled collectors sorted.
+func enabledCollectorNames(enabled map[string]bool) []string {
+	names := []string{}
+	for _, name := range collectorNames() {
+		if enabled[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCollectorFlags(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		rest []string
+		// changed has the collectors whose state differs from defaultCollectors.
+		changed map[string]bool
+		err     bool
+	}{
+		{
+			name: "none",
+			rest: []string{},
+		},
+		{
+			name:    "enable and disable",
+			args:    []string{"--collector.cost", "--no-collector.droplet"},
+			rest:    []string{},
+			changed: map[string]bool{"cost": true, "droplet": false},
+		},
+		{
+			name: "enable enabled by default",
+			args: []string{"--collector.droplet", "--no-collector.cost"},
+			rest: []string{},
+		},
+		{
+			name:    "spaces",
+			args:    []string{"--no-collector.spaces"},
+			rest:    []string{},
+			changed: map[string]bool{"spaces": false},
+		},
+		{
+			name:    "repeated",
+			args:    []string{"--collector.cost", "--collector.cost"},
+			rest:    []string{},
+			changed: map[string]bool{"cost": true},
+		},
+		{
+			name: "conflicting last wins",
+			args: []string{"--collector.cost", "--no-collector.cost", "--no-collector.droplet", "--collector.droplet"},
+			rest: []string{},
+		},
+		{
+			name:    "values",
+			args:    []string{"--collector.droplet=false", "--collector.cost=true", "--no-collector.droplet_backup=false", "--no-collector.vpc=true"},
+			rest:    []string{},
+			changed: map[string]bool{"droplet": false, "cost": true, "droplet_backup": true, "vpc": false},
+		},
+		{
+			name: "invalid value",
+			args: []string{"--collector.droplet=maybe"},
+			err:  true,
+		},
+		{
+			name: "empty value",
+			args: []string{"--collector.droplet="},
+			err:  true,
+		},
+		{
+			name: "unknown",
+			args: []string{"--collector.unknown"},
+			err:  true,
+		},
+		{
+			name: "unknown disabled",
+			args: []string{"--no-collector.unknown"},
+			err:  true,
+		},
+		{
+			name: "unknown with value",
+			args: []string{"--collector.unknown=false"},
+			err:  true,
+		},
+		{
+			name: "empty name",
+			args: []string{"--collector."},
+			err:  true,
+		},
+		{
+			name:    "passthrough",
+			args:    []string{"--web.addr=:9212", "--no-collector.droplet", "-debug", "--collectors", "droplet", "--collector-cost"},
+			rest:    []string{"--web.addr=:9212", "-debug", "--collectors", "droplet", "--collector-cost"},
+			changed: map[string]bool{"droplet": false},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rest, enabled, err := parseCollectorFlags(tc.args)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got args %v and collectors %v", rest, enabled)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rest, tc.rest) {
+				t.Errorf("expected args %v, got %v", tc.rest, rest)
+			}
+
+			expected := make(map[string]bool, len(defaultCollectors))
+			for name, e := range defaultCollectors {
+				expected[name] = e
+			}
+			for name, e := range tc.changed {
+				expected[name] = e
+			}
+			if !reflect.DeepEqual(enabled, expected) {
+				t.Errorf("expected collectors %v, got %v", expected, enabled)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	arg "github.com/alexflint/go-arg"
@@ -40,11 +41,24 @@ type Config struct {
 	HTTPTimeout           int    `arg:"env:HTTP_TIMEOUT"`
 	WebAddr               string `arg:"env:WEB_ADDR"`
 	WebPath               string `arg:"env:WEB_PATH"`
+
+	RefreshInterval  time.Duration            `arg:"env:REFRESH_INTERVAL"`
+	RefreshIntervals map[string]time.Duration `arg:"env:REFRESH_INTERVALS"`
+
+	SDPort         int           `arg:"env:SD_PORT"`
+	SDFile         string        `arg:"env:SD_FILE"`
+	SDFileInterval time.Duration `arg:"env:SD_FILE_INTERVAL"`
+	SDFileTag      string        `arg:"env:SD_FILE_TAG"`
+	SDFileRegion   string        `arg:"env:SD_FILE_REGION"`
+
+	AlertPolicyTag string `arg:"env:ALERT_POLICY_TAG"`
+	PricesFile     string `arg:"env:PRICES_FILE"`
 }
 
-// Token returns a token or an error.
-func (c Config) Token() (*oauth2.Token, error) {
-	return &oauth2.Token{AccessToken: c.DigitalOceanToken}, nil
+// Description is printed at the top of the help, explaining the collector flags go-arg doesn't know about.
+func (Config) Description() string {
+	return "Collectors are enabled with --collector.<name> and disabled with --no-collector.<name>.\n" +
+		"Available collectors: " + strings.Join(collectorNames(), ", ") + "\n"
 }
 
 func main() {
@@ -54,10 +68,36 @@ func main() {
 		HTTPTimeout: 5000,
 		WebPath:     "/metrics",
 		WebAddr:     ":9212",
+
+		SDPort:         80,
+		SDFileInterval: time.Minute,
+
+		RefreshIntervals: map[string]time.Duration{},
+
+		AlertPolicyTag: "prod",
+	}
+
+	p, err := arg.NewParser(arg.Config{}, &c)
+	if err != nil {
+		panic(err)
+	}
+	args, enabled, err := parseCollectorFlags(os.Args[1:])
+	if err != nil {
+		p.Fail(err.Error())
+	}
+	switch err := p.Parse(args); {
+	case err == arg.ErrHelp:
+		p.WriteHelp(os.Stdout)
+		os.Exit(0)
+	case err != nil:
+		p.Fail(err.Error())
 	}
-	arg.MustParse(&c)
 
-	if c.DigitalOceanToken == "" {
+	accounts, err := accountsFromEnv(c, os.Environ())
+	if err != nil {
+		panic(err)
+	}
+	if len(accounts) == 0 {
 		panic("DigitalOcean Token is required")
 	}
 
@@ -80,51 +120,44 @@ func main() {
 		"buildDate", BuildDate,
 		"goVersion", GoVersion,
 	)
-
-	if c.SpacesAccessKeyID == "" && c.SpacesAccessKeySecret == "" {
-		level.Warn(logger).Log(
-			"msg", "Spaces Access Key ID and Secret unset. Spaces buckets will not be collected",
-		)
-	}
-
-	oauthClient := oauth2.NewClient(context.TODO(), c)
-	client := godo.NewClient(oauthClient)
+	level.Info(logger).Log(
+		"msg", "enabled collectors",
+		"collectors", strings.Join(enabledCollectorNames(enabled), ","),
+	)
 
 	timeout := time.Duration(c.HTTPTimeout) * time.Millisecond
 
-	errors := prometheus.NewCounterVec(prometheus.CounterOpts{
-		Name: "digitalocean_errors_total",
-		Help: "The total number of errors per collector",
-	}, []string{"collector"})
+	prices := collector.DefaultPrices()
+	if c.PricesFile != "" {
+		if prices, err = collector.LoadPrices(c.PricesFile); err != nil {
+			panic(err)
+		}
+	}
 
 	r := prometheus.NewRegistry()
 	r.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
 	r.MustRegister(prometheus.NewGoCollector())
-	r.MustRegister(errors)
 	r.MustRegister(collector.NewExporterCollector(logger, Version, Revision, BuildDate, GoVersion, StartTime))
-	r.MustRegister(collector.NewAccountCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewAppCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewBalanceCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewDBCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewDomainCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewDropletCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewFloatingIPCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewImageCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewKeyCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewLoadBalancerCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewSnapshotCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewVolumeCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewKubernetesCollector(logger, errors, client, timeout))
-	r.MustRegister(collector.NewIncidentCollector(logger, errors, timeout))
 
-	// Only run spaces bucket collector if access key id and secret are set
-	if c.SpacesAccessKeyID != "" && c.SpacesAccessKeySecret != "" {
-		r.MustRegister(collector.NewSpacesCollector(logger, errors, client, c.SpacesAccessKeyID, c.SpacesAccessKeySecret, timeout))
+	registerGlobal(logger, r, c, enabled, timeout)
+
+	discovery := collector.NewDropletDiscovery(logger, timeout, c.SDPort)
+	for _, account := range accounts {
+		level.Info(logger).Log("msg", "collecting account", "account", account.Name)
+		registerAccount(logger, r, discovery, c, enabled, prices, account, timeout)
+	}
+
+	if c.SDFile != "" {
+		go discovery.RunFile(context.Background(), c.SDFile, collector.DropletFilter{
+			Tag:    c.SDFileTag,
+			Region: c.SDFileRegion,
+		}, c.SDFileInterval)
 	}
 
 	http.Handle(c.WebPath,
 		promhttp.HandlerFor(r, promhttp.HandlerOpts{}),
 	)
+	http.Handle("/sd/droplets", discovery)
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte(`<html>
@@ -132,6 +165,7 @@ func main() {
 			<body>
 			<h1>DigitalOcean Exporter</h1>
 			<p><a href="` + c.WebPath + `">Metrics</a></p>
+			<p><a href="/sd/droplets">Droplet Service Discovery</a></p>
 			</body>
 			</html>`))
 	})
@@ -142,3 +176,162 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// registerGlobal registers all collectors that don't depend on an account.
+// They are only registered once, as they would collect the same metrics for every account.
+// Their metrics have an empty account label, as metrics of the same name need to have the same labels.
+func registerGlobal(logger log.Logger, r prometheus.Registerer, c Config, enabled map[string]bool, timeout time.Duration) {
+	r = prometheus.WrapRegistererWith(prometheus.Labels{"account": ""}, r)
+
+	errors := newErrors()
+	r.MustRegister(errors)
+
+	for name, newCollector := range globalCollectors(logger, errors, timeout) {
+		if !enabled[name] {
+			continue
+		}
+		registerCollector(logger, r, c, name, newCollector(), errors.WithLabelValues(name))
+	}
+}
+
+// registerAccount registers all collectors for the account,
+// labeling each of their metrics with the account's name.
+func registerAccount(logger log.Logger, r prometheus.Registerer, discovery *collector.DropletDiscovery, c Config, enabled map[string]bool, prices collector.Prices, account Account, timeout time.Duration) {
+	logger = log.With(logger, "account", account.Name)
+	r = prometheus.WrapRegistererWith(prometheus.Labels{"account": account.Name}, r)
+
+	if enabled["spaces"] && !account.HasSpacesKeys() {
+		level.Warn(logger).Log(
+			"msg", "Spaces Access Key ID and Secret unset. Spaces buckets will not be collected",
+		)
+	}
+
+	oauthClient := oauth2.NewClient(context.TODO(), account)
+	client := godo.NewClient(oauthClient)
+
+	rateLimits := collector.NewRateLimitCollector()
+	client.OnRequestCompleted(rateLimits.Observe)
+	r.MustRegister(rateLimits)
+
+	errors := newErrors()
+	r.MustRegister(errors)
+
+	for name, newCollector := range accountCollectors(logger, errors, client, prices, account, c.AlertPolicyTag, timeout) {
+		if !enabled[name] {
+			continue
+		}
+		label := name
+		if l, ok := collectorErrorsLabels[name]; ok {
+			label = l
+		}
+		registerCollector(logger, r, c, name, newCollector(), errors.WithLabelValues(label))
+	}
+
+	discovery.AddAccount(account.Name, errors, client)
+}
+
+// newErrors returns the counter of errors per collector.
+func newErrors() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "digitalocean_errors_total",
+		Help: "The total number of errors per collector",
+	}, []string{"collector"})
+}
+
+// registerCollector registers the collector instrumented,
+// and refreshed in the background if it has a refresh interval.
+// errors has to be the counter the collector increments on errors.
+func registerCollector(logger log.Logger, r prometheus.Registerer, c Config, name string, col prometheus.Collector, errors prometheus.Counter) {
+	col = collector.NewInstrumentedCollector(logger, name, col, errors)
+
+	// Collectors with a refresh interval are collected in the background,
+	// all others call the API on every scrape.
+	interval := c.RefreshInterval
+	if i, ok := c.RefreshIntervals[name]; ok {
+		interval = i
+	}
+	if interval > 0 {
+		cached := collector.NewCachedCollector(logger, name, col, errors, interval)
+		go cached.Run(context.Background())
+		col = cached
+	}
+	r.MustRegister(col)
+}
+
+// globalCollectors returns the constructors of all collectors not depending on an account by their name.
+// Every collector counts its errors with its name as the errors' collector label.
+func globalCollectors(logger log.Logger, errors *prometheus.CounterVec, timeout time.Duration) map[string]func() prometheus.Collector {
+	return map[string]func() prometheus.Collector{
+		"incidents": func() prometheus.Collector { return collector.NewIncidentCollector(logger, errors, timeout) },
+	}
+}
+
+// collectorErrorsLabels has the errors' collector label of the collectors not counting their errors with their name.
+var collectorErrorsLabels = map[string]string{
+	// The spaces collector counts its errors like its metrics are named.
+	"spaces": "spaces_bucket",
+}
+
+// accountCollectors returns the constructors of all collectors available for the account by their name.
+// Every collector counts its errors with its name as the errors' collector label, unless it's in collectorErrorsLabels.
+func accountCollectors(logger log.Logger, errors *prometheus.CounterVec, client *godo.Client, prices collector.Prices, account Account, alertPolicyTag string, timeout time.Duration) map[string]func() prometheus.Collector {
+	collectors := map[string]func() prometheus.Collector{
+		"account":     func() prometheus.Collector { return collector.NewAccountCollector(logger, errors, client, timeout) },
+		"app":         func() prometheus.Collector { return collector.NewAppCollector(logger, errors, client, timeout) },
+		"balance":     func() prometheus.Collector { return collector.NewBalanceCollector(logger, errors, client, timeout) },
+		"catalog":     func() prometheus.Collector { return collector.NewCatalogCollector(logger, errors, client, timeout) },
+		"certificate": func() prometheus.Collector { return collector.NewCertificateCollector(logger, errors, client, timeout) },
+		"database":    func() prometheus.Collector { return collector.NewDBCollector(logger, errors, client, timeout) },
+		"domain":      func() prometheus.Collector { return collector.NewDomainCollector(logger, errors, client, timeout) },
+		"droplet":     func() prometheus.Collector { return collector.NewDropletCollector(logger, errors, client, timeout) },
+		"firewall":    func() prometheus.Collector { return collector.NewFirewallCollector(logger, errors, client, timeout) },
+		"floating_ip": func() prometheus.Collector { return collector.NewFloatingIPCollector(logger, errors, client, timeout) },
+		"image":       func() prometheus.Collector { return collector.NewImageCollector(logger, errors, client, timeout) },
+		"key":         func() prometheus.Collector { return collector.NewKeyCollector(logger, errors, client, timeout) },
+		"loadbalancer": func() prometheus.Collector {
+			return collector.NewLoadBalancerCollector(logger, errors, client, timeout)
+		},
+		"droplet_utilization": func() prometheus.Collector {
+			return collector.NewDropletUtilizationCollector(logger, errors, client, timeout)
+		},
+		"droplet_backup": func() prometheus.Collector {
+			return collector.NewDropletBackupCollector(logger, errors, client, timeout)
+		},
+		"alert_policy": func() prometheus.Collector {
+			return collector.NewAlertPolicyCollector(logger, errors, client, alertPolicyTag, timeout)
+		},
+		"reserved_ip": func() prometheus.Collector {
+			return collector.NewReservedIPCollector(logger, errors, client, timeout)
+		},
+		"invoice": func() prometheus.Collector {
+			return collector.NewInvoiceCollector(logger, errors, client, timeout)
+		},
+		"project":    func() prometheus.Collector { return collector.NewProjectCollector(logger, errors, client, timeout) },
+		"registry":   func() prometheus.Collector { return collector.NewRegistryCollector(logger, errors, client, timeout) },
+		"snapshot":   func() prometheus.Collector { return collector.NewSnapshotCollector(logger, errors, client, timeout) },
+		"tag":        func() prometheus.Collector { return collector.NewTagCollector(logger, errors, client, timeout) },
+		"uptime":     func() prometheus.Collector { return collector.NewUptimeCollector(logger, errors, client, timeout) },
+		"volume":     func() prometheus.Collector { return collector.NewVolumeCollector(logger, errors, client, timeout) },
+		"vpc":        func() prometheus.Collector { return collector.NewVPCCollector(logger, errors, client, timeout) },
+		"kubernetes": func() prometheus.Collector { return collector.NewKubernetesCollector(logger, errors, client, timeout) },
+	}
+
+	// The cdn collector only checks the origin buckets if the spaces keys are set
+	collectors["cdn"] = func() prometheus.Collector {
+		return collector.NewCDNCollector(logger, errors, client, account.SpacesAccessKeyID, account.SpacesAccessKeySecret, timeout)
+	}
+
+	// The cost collector only includes the Spaces subscription if the spaces keys are set
+	collectors["cost"] = func() prometheus.Collector {
+		return collector.NewCostCollector(logger, errors, client, account.SpacesAccessKeyID, account.SpacesAccessKeySecret, prices, timeout)
+	}
+
+	// Only run spaces bucket collector if access key id and secret are set
+	if account.HasSpacesKeys() {
+		collectors["spaces"] = func() prometheus.Collector {
+			return collector.NewSpacesCollector(logger, errors, client, account.SpacesAccessKeyID, account.SpacesAccessKeySecret, timeout)
+		}
+	}
+
+	return collectors
+}
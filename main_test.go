@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/digitalocean/godo"
+	"github.com/go-kit/kit/log"
+	"github.com/metalmatze/digitalocean_exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestCollectorsErrorsLabel makes sure every collector counts its errors with its name,
+// or its label in collectorErrorsLabels, as the scrape success of a collector is told by these errors.
+func TestCollectorsErrorsLabel(t *testing.T) {
+	account := Account{
+		Name:                  defaultAccount,
+		AccessToken:           "token",
+		SpacesAccessKeyID:     "access-key-id",
+		SpacesAccessKeySecret: "access-key-secret",
+	}
+
+	constructors := func(errors *prometheus.CounterVec) map[string]func() prometheus.Collector {
+		logger := log.NewNopLogger()
+		collectors := accountCollectors(logger, errors, godo.NewClient(nil), collector.DefaultPrices(), account, "prod", time.Second)
+		for name, newCollector := range globalCollectors(logger, errors, time.Second) {
+			if _, ok := collectors[name]; ok {
+				t.Errorf("collector %s is both a global and an account collector", name)
+			}
+			collectors[name] = newCollector
+		}
+		return collectors
+	}
+
+	names := constructors(newErrors())
+	for _, name := range collectorNames() {
+		if _, ok := names[name]; !ok {
+			t.Errorf("collector %s has no constructor", name)
+		}
+	}
+
+	for name := range names {
+		errors := newErrors()
+		constructors(errors)[name]()
+
+		r := prometheus.NewRegistry()
+		r.MustRegister(errors)
+		mfs, err := r.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var labels []string
+		for _, mf := range mfs {
+			for _, m := range mf.GetMetric() {
+				for _, l := range m.GetLabel() {
+					labels = append(labels, l.GetValue())
+				}
+			}
+		}
+		label := name
+		if l, ok := collectorErrorsLabels[name]; ok {
+			label = l
+		}
+		if len(labels) != 1 || labels[0] != label {
+			t.Errorf("expected collector %s to count errors as %s, got %v", name, label, labels)
+		}
+	}
+}
+
+// TestRegister makes sure the metrics of global and account collectors can be registered next to each other.
+func TestRegister(t *testing.T) {
+	logger := log.NewNopLogger()
+	r := prometheus.NewPedanticRegistry()
+	discovery := collector.NewDropletDiscovery(logger, time.Second, 80)
+	enabled := map[string]bool{}
+	for _, name := range collectorNames() {
+		enabled[name] = true
+	}
+
+	registerGlobal(logger, r, Config{}, enabled, time.Second)
+	for _, name := range []string{"default", "payments"} {
+		registerAccount(logger, r, discovery, Config{}, enabled, collector.DefaultPrices(), Account{Name: name, AccessToken: "token"}, time.Second)
+	}
+}